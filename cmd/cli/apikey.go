@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// Variables qui stockeront les valeurs des flags de la commande apikey create
+var (
+	apiKeyNameFlag   string
+	apiKeyScopesFlag []string
+)
+
+// APIKeyCmd regroupe les commandes de gestion des clés d'API.
+var APIKeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "Gère les clés d'accès à l'API de gestion (/api/v1).",
+	Long: `Les clés d'API authentifient les appels à /api/v1 via l'en-tête
+"Authorization: Bearer <clé>" ou "X-API-Key: <clé>". Chaque clé porte des portées :
+  links:read   lister les liens
+  links:write  créer, modifier et supprimer des liens
+  stats:read   consulter les statistiques`,
+}
+
+// APIKeyCreateCmd représente la commande 'apikey create'
+var APIKeyCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Crée une nouvelle clé d'API et l'affiche une seule fois.",
+	Long: `Cette commande génère une clé d'API avec les portées demandées.
+Seul son hash est enregistré : notez la clé affichée, elle ne pourra plus être récupérée.
+
+Exemple:
+  url-shortener apikey create --name="site marketing" --scopes=links:write,stats:read`,
+	Run: func(cmd *cobra.Command, args []string) {
+		db, closeDB := openDatabase()
+		defer closeDB()
+
+		apiKeyService := services.NewAPIKeyService(repository.NewAPIKeyRepository(db))
+
+		raw, key, err := apiKeyService.CreateAPIKey(apiKeyNameFlag, apiKeyScopesFlag)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidScope) || errors.Is(err, services.ErrAPIKeyName) {
+				fmt.Printf("Erreur: %v\n", err)
+			} else {
+				fmt.Printf("Erreur lors de la création de la clé: %v\n", err)
+			}
+			os.Exit(1)
+		}
+
+		fmt.Printf("Clé d'API créée (ID %d, portées: %s).\n", key.ID, strings.Join(key.ScopeList(), ", "))
+		fmt.Println("Conservez-la maintenant, elle ne sera plus affichée :")
+		fmt.Println(raw)
+	},
+}
+
+// APIKeyListCmd représente la commande 'apikey list'
+var APIKeyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Liste les clés d'API, révoquées comprises.",
+	Run: func(cmd *cobra.Command, args []string) {
+		db, closeDB := openDatabase()
+		defer closeDB()
+
+		apiKeyService := services.NewAPIKeyService(repository.NewAPIKeyRepository(db))
+
+		keys, err := apiKeyService.ListAPIKeys()
+		if err != nil {
+			fmt.Printf("Erreur lors de la récupération des clés: %v\n", err)
+			os.Exit(1)
+		}
+		if len(keys) == 0 {
+			fmt.Println("Aucune clé d'API.")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tPRÉFIXE\tNOM\tPORTÉES\tCRÉÉE LE\tDERNIÈRE UTILISATION\tSTATUT")
+		for _, key := range keys {
+			fmt.Fprintf(w, "%d\t%s…\t%s\t%s\t%s\t%s\t%s\n", key.ID, key.Prefix, key.Name,
+				strings.Join(key.ScopeList(), ","), key.CreatedAt.Format("2006-01-02 15:04"),
+				formatOptionalTime(key.LastUsedAt), apiKeyStatus(key))
+		}
+		w.Flush()
+	},
+}
+
+// APIKeyRevokeCmd représente la commande 'apikey revoke'
+var APIKeyRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Révoque une clé d'API.",
+	Long: `Cette commande révoque définitivement une clé d'API : les requêtes qui la présentent
+sont refusées (401). L'identifiant est celui affiché par 'apikey list'.
+
+Exemple:
+  url-shortener apikey revoke 3`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil || id == 0 {
+			fmt.Printf("Erreur: Identifiant de clé invalide '%s'\n", args[0])
+			os.Exit(1)
+		}
+
+		db, closeDB := openDatabase()
+		defer closeDB()
+
+		apiKeyService := services.NewAPIKeyService(repository.NewAPIKeyRepository(db))
+
+		if err := apiKeyService.RevokeAPIKey(uint(id)); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				fmt.Printf("Erreur: Aucune clé d'API avec l'identifiant %d\n", id)
+			} else {
+				fmt.Printf("Erreur lors de la révocation de la clé: %v\n", err)
+			}
+			os.Exit(1)
+		}
+
+		fmt.Printf("Clé d'API %d révoquée.\n", id)
+	},
+}
+
+// apiKeyStatus retourne le statut lisible d'une clé d'API.
+func apiKeyStatus(key models.APIKey) string {
+	if key.IsRevoked() {
+		return "révoquée le " + key.RevokedAt.Format("2006-01-02 15:04")
+	}
+	return "active"
+}
+
+// formatOptionalTime formate une date optionnelle pour l'affichage en tableau.
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+func init() {
+	APIKeyCreateCmd.Flags().StringVarP(&apiKeyNameFlag, "name", "n", "", "Libellé de la clé")
+	APIKeyCreateCmd.Flags().StringSliceVarP(&apiKeyScopesFlag, "scopes", "s", nil,
+		"Portées de la clé, séparées par des virgules ("+strings.Join(models.AllScopes, ", ")+")")
+	APIKeyCreateCmd.MarkFlagRequired("name")
+	APIKeyCreateCmd.MarkFlagRequired("scopes")
+
+	APIKeyCmd.AddCommand(APIKeyCreateCmd, APIKeyListCmd, APIKeyRevokeCmd)
+	cmd2.RootCmd.AddCommand(APIKeyCmd)
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// Variables qui stockeront les valeurs des flags de la commande audit
+var (
+	auditCodeFlag  string
+	auditLimitFlag int
+)
+
+// AuditCmd représente la commande 'audit'
+var AuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Affiche le journal d'audit d'un lien.",
+	Long: `Cette commande affiche les mesures appliquées automatiquement à un lien par le moniteur
+d'URLs (désactivation, bascule vers l'URL de repli, rétablissement), de la plus récente à la plus ancienne.
+
+Exemple:
+  url-shortener audit --code="xyz123"
+  url-shortener audit --code="xyz123" --limit=100`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if auditCodeFlag == "" {
+			fmt.Println("Erreur: Le flag --code est obligatoire")
+			os.Exit(1)
+		}
+
+		db, closeDB := openDatabase()
+		defer closeDB()
+
+		linkService := services.NewLinkService(repository.NewLinkRepository(db))
+		auditService := services.NewAuditService(repository.NewLinkAuditRepository(db))
+
+		link, err := linkService.GetLinkByShortCode(auditCodeFlag)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				fmt.Printf("Erreur: Aucun lien trouvé avec le code '%s'\n", auditCodeFlag)
+			} else {
+				fmt.Printf("Erreur lors de la récupération du lien: %v\n", err)
+			}
+			os.Exit(1)
+		}
+
+		events, err := auditService.GetLinkAudit(link.ID, auditLimitFlag)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidAuditParams) {
+				fmt.Printf("Erreur: Paramètres invalides: %v\n", err)
+			} else {
+				fmt.Printf("Erreur lors de la récupération du journal d'audit: %v\n", err)
+			}
+			os.Exit(1)
+		}
+
+		fmt.Printf("Journal d'audit du lien: %s\n", link.ShortCode)
+		fmt.Printf("Mesure en cours: %s\n", healthActionLabel(link.HealthAction))
+		if link.DownSince != nil {
+			fmt.Printf("Destination inaccessible depuis: %s\n", link.DownSince.Local().Format("2006-01-02 15:04:05"))
+		}
+
+		if len(events) == 0 {
+			fmt.Println("\nAucune entrée.")
+			return
+		}
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "DATE\tAUTEUR\tACTION\tDÉTAIL")
+		for _, event := range events {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", event.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+				event.Actor, event.Action, event.Detail)
+		}
+		w.Flush()
+	},
+}
+
+// healthActionLabel retourne le libellé d'une mesure du moniteur pour l'affichage.
+func healthActionLabel(action string) string {
+	switch action {
+	case models.HealthActionDisable:
+		return "lien désactivé (destination inaccessible)"
+	case models.HealthActionFallback:
+		return "redirection vers l'URL de repli"
+	default:
+		return "aucune"
+	}
+}
+
+func init() {
+	AuditCmd.Flags().StringVarP(&auditCodeFlag, "code", "c", "", "Code court du lien")
+	AuditCmd.Flags().IntVarP(&auditLimitFlag, "limit", "l", services.DefaultAuditLimit, "Nombre d'entrées affichées")
+	AuditCmd.MarkFlagRequired("code")
+
+	cmd2.RootCmd.AddCommand(AuditCmd)
+}
@@ -5,18 +5,32 @@ import (
 	"log"
 	"net/url" // Pour valider le format de l'URL
 	"os"
+	"time"
 
 	cmd2 "github.com/axellelanca/urlshortener/cmd"
 	"github.com/axellelanca/urlshortener/internal/repository"
 	"github.com/axellelanca/urlshortener/internal/services"
 	"github.com/spf13/cobra"
-	"gorm.io/driver/sqlite" // Driver SQLite pour GORM
-	"gorm.io/gorm"
 )
 
 // Faire une variable longURLFlag qui stockera la valeur du flag --url
 var longURLFlag string
 
+// aliasFlag stocke la valeur du flag optionnel --alias
+var aliasFlag string
+
+// expiresAtFlag et maxClicksFlag stockent la durée de vie optionnelle du lien
+var (
+	expiresAtFlag string
+	maxClicksFlag int
+)
+
+// fallbackURLFlag stocke l'URL de repli optionnelle du lien
+var fallbackURLFlag string
+
+// redirectTypeFlag stocke le code de redirection optionnel du lien (0 = links.default_redirect_type)
+var redirectTypeFlag int
+
 // CreateCmd représente la commande 'create'
 var CreateCmd = &cobra.Command{
 	Use:   "create",
@@ -24,7 +38,11 @@ var CreateCmd = &cobra.Command{
 	Long: `Cette commande raccourcit une URL longue fournie et affiche le code court généré.
 
 Exemple:
-  url-shortener create --url="https://www.google.com/search?q=go+lang"`,
+  url-shortener create --url="https://www.google.com/search?q=go+lang"
+  url-shortener create --url="https://www.example.com/promo" --alias="spring-sale"
+  url-shortener create --url="https://www.example.com/promo" --expires-at="2025-12-31T23:59:59Z" --max-clicks=100
+  url-shortener create --url="https://www.example.com/promo" --fallback-url="https://www.example.com"
+  url-shortener create --url="https://www.example.com/produit" --redirect-type=301`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Valider que le flag --url a été fourni.
 		if longURLFlag == "" {
@@ -39,14 +57,41 @@ Exemple:
 			os.Exit(1)
 		}
 
+		// Valider l'alias personnalisé avant d'ouvrir la base de données.
+		if aliasFlag != "" {
+			if err := services.ValidateAlias(aliasFlag); err != nil {
+				fmt.Printf("Erreur: Alias invalide '%s': %v\n", aliasFlag, err)
+				os.Exit(1)
+			}
+		}
+
+		// Valider le code de redirection optionnel.
+		if redirectTypeFlag != 0 {
+			if err := services.ValidateRedirectType(redirectTypeFlag); err != nil {
+				fmt.Printf("Erreur: Type de redirection invalide: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		// Parser la date d'expiration optionnelle (format RFC 3339).
+		var expiresAt *time.Time
+		if expiresAtFlag != "" {
+			t, err := time.Parse(time.RFC3339, expiresAtFlag)
+			if err != nil {
+				fmt.Printf("Erreur: Date d'expiration invalide '%s' (format attendu RFC 3339): %v\n", expiresAtFlag, err)
+				os.Exit(1)
+			}
+			expiresAt = &t
+		}
+
 		// Charger la configuration chargée globalement via cmd.cfg
 		cfg := cmd2.Cfg
 		if cfg == nil {
 			log.Fatal("FATAL: Configuration non initialisée")
 		}
 
-		// Initialiser la connexion à la base de données SQLite.
-		db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{})
+		// Initialiser la connexion à la base de données configurée.
+		db, err := cmd2.OpenDatabase(cfg)
 		if err != nil {
 			log.Fatalf("FATAL: Impossible de se connecter à la base de données: %v", err)
 		}
@@ -62,9 +107,22 @@ Exemple:
 		// Initialiser les repositories et services nécessaires NewLinkRepository & NewLinkService
 		linkRepo := repository.NewLinkRepository(db)
 		linkService := services.NewLinkService(linkRepo)
+		urlValidator, _, err := cmd2.NewURLValidator(cfg)
+		if err != nil {
+			log.Fatalf("FATAL: Impossible de charger la liste de domaines bloqués: %v", err)
+		}
+		linkService.SetURLValidator(urlValidator)
+		linkService.SetDefaultRedirectType(cfg.Links.DefaultRedirectType)
 
 		// Appeler le LinkService et la fonction CreateLink pour créer le lien court.
-		link, err := linkService.CreateLink(longURLFlag)
+		link, err := linkService.CreateLink(longURLFlag, services.CreateLinkOptions{
+			CustomAlias: aliasFlag,
+			ExpiresAt:   expiresAt,
+			MaxClicks:   maxClicksFlag,
+			FallbackURL: fallbackURLFlag,
+
+			RedirectType: redirectTypeFlag,
+		})
 		if err != nil {
 			fmt.Printf("Erreur lors de la création du lien: %v\n", err)
 			os.Exit(1)
@@ -74,6 +132,16 @@ Exemple:
 		fmt.Printf("URL courte créée avec succès:\n")
 		fmt.Printf("Code: %s\n", link.ShortCode)
 		fmt.Printf("URL complète: %s\n", fullShortURL)
+		if link.ExpiresAt != nil {
+			fmt.Printf("Expire le: %s\n", link.ExpiresAt.Format(time.RFC3339))
+		}
+		if link.MaxClicks > 0 {
+			fmt.Printf("Budget de clics: %d\n", link.MaxClicks)
+		}
+		if link.FallbackURL != "" {
+			fmt.Printf("URL de repli: %s\n", link.FallbackURL)
+		}
+		fmt.Printf("Redirection: %d\n", link.RedirectType)
 	},
 }
 
@@ -82,6 +150,11 @@ Exemple:
 func init() {
 	// Définir le flag --url pour la commande create.
 	CreateCmd.Flags().StringVarP(&longURLFlag, "url", "u", "", "URL longue à raccourcir")
+	CreateCmd.Flags().StringVarP(&aliasFlag, "alias", "a", "", "Alias personnalisé optionnel à utiliser comme code court")
+	CreateCmd.Flags().StringVar(&expiresAtFlag, "expires-at", "", "Date d'expiration optionnelle au format RFC 3339")
+	CreateCmd.Flags().IntVar(&maxClicksFlag, "max-clicks", 0, "Nombre maximal de redirections (0 = illimité)")
+	CreateCmd.Flags().StringVar(&fallbackURLFlag, "fallback-url", "", "URL de repli si la destination devient durablement inaccessible")
+	CreateCmd.Flags().IntVar(&redirectTypeFlag, "redirect-type", 0, "Code de la redirection : 301, 302, 307 ou 308 (défaut : links.default_redirect_type)")
 
 	// Marquer le flag comme requis
 	CreateCmd.MarkFlagRequired("url")
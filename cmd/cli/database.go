@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"log"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"gorm.io/gorm"
+)
+
+// openDatabase ouvre la connexion à la base de données configurée pour une commande CLI.
+// Elle termine le programme en cas d'erreur et retourne une fonction de fermeture
+// à différer jusqu'à la fin de la commande.
+func openDatabase() (*gorm.DB, func()) {
+	// Charger la configuration chargée globalement via cmd.cfg
+	cfg := cmd2.Cfg
+	if cfg == nil {
+		log.Fatal("FATAL: Configuration non initialisée")
+	}
+
+	db, err := cmd2.OpenDatabase(cfg)
+	if err != nil {
+		log.Fatalf("FATAL: Impossible de se connecter à la base de données: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("FATAL: Échec de l'obtention de la base de données SQL sous-jacente: %v", err)
+	}
+
+	return db, func() { sqlDB.Close() }
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// deleteCodeFlag stocke la valeur du flag --code de la commande delete
+var deleteCodeFlag string
+
+// DeleteCmd représente la commande 'delete'
+var DeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Supprime un lien court.",
+	Long: `Cette commande supprime logiquement un lien court : il ne redirige plus,
+mais son historique de clics est conservé et son code ne sera jamais réattribué.
+
+Exemple:
+  url-shortener delete --code="xyz123"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if deleteCodeFlag == "" {
+			fmt.Println("Erreur: Le flag --code est obligatoire")
+			os.Exit(1)
+		}
+
+		db, closeDB := openDatabase()
+		defer closeDB()
+
+		linkService := services.NewLinkService(repository.NewLinkRepository(db))
+		linkService.SetAuditActor(models.AuditActorCLI)
+
+		if err := linkService.DeleteLink(deleteCodeFlag); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				fmt.Printf("Erreur: Aucun lien trouvé avec le code '%s'\n", deleteCodeFlag)
+			} else {
+				fmt.Printf("Erreur lors de la suppression du lien: %v\n", err)
+			}
+			os.Exit(1)
+		}
+
+		fmt.Printf("Lien %s supprimé.\n", deleteCodeFlag)
+	},
+}
+
+func init() {
+	DeleteCmd.Flags().StringVarP(&deleteCodeFlag, "code", "c", "", "Code court du lien à supprimer")
+	DeleteCmd.MarkFlagRequired("code")
+
+	cmd2.RootCmd.AddCommand(DeleteCmd)
+}
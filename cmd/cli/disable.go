@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// Variables qui stockeront les valeurs des flags de la commande disable
+var (
+	disableCodeFlag string
+	enableFlag      bool
+)
+
+// DisableCmd représente la commande 'disable'
+var DisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Désactive (ou réactive) la redirection d'un lien court.",
+	Long: `Cette commande désactive un lien court : les visites reçoivent une réponse 410 Gone
+au lieu d'être redirigées. Utilisez --enable pour réactiver le lien.
+
+Exemple:
+  url-shortener disable --code="xyz123"
+  url-shortener disable --code="xyz123" --enable`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if disableCodeFlag == "" {
+			fmt.Println("Erreur: Le flag --code est obligatoire")
+			os.Exit(1)
+		}
+
+		db, closeDB := openDatabase()
+		defer closeDB()
+
+		linkService := services.NewLinkService(repository.NewLinkRepository(db))
+		linkService.SetAuditActor(models.AuditActorCLI)
+
+		disabled := !enableFlag
+		link, err := linkService.UpdateLink(disableCodeFlag, services.UpdateLinkOptions{Disabled: &disabled})
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				fmt.Printf("Erreur: Aucun lien trouvé avec le code '%s'\n", disableCodeFlag)
+			} else {
+				fmt.Printf("Erreur lors de la modification du lien: %v\n", err)
+			}
+			os.Exit(1)
+		}
+
+		if link.Disabled {
+			fmt.Printf("Lien %s désactivé.\n", link.ShortCode)
+		} else {
+			fmt.Printf("Lien %s réactivé.\n", link.ShortCode)
+		}
+	},
+}
+
+func init() {
+	DisableCmd.Flags().StringVarP(&disableCodeFlag, "code", "c", "", "Code court du lien à désactiver")
+	DisableCmd.Flags().BoolVar(&enableFlag, "enable", false, "Réactive le lien au lieu de le désactiver")
+	DisableCmd.MarkFlagRequired("code")
+
+	cmd2.RootCmd.AddCommand(DisableCmd)
+}
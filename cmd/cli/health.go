@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// Variables qui stockeront les valeurs des flags de la commande health
+var (
+	healthCodeFlag   string
+	healthWindowFlag time.Duration
+	healthLimitFlag  int
+)
+
+// HealthCmd représente la commande 'health'
+var HealthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Affiche la disponibilité et l'historique de surveillance d'un lien.",
+	Long: `Cette commande affiche l'état de la destination d'un lien d'après le moniteur d'URLs :
+disponibilité sur la fenêtre demandée et dernières vérifications.
+
+Exemple:
+  url-shortener health --code="xyz123"
+  url-shortener health --code="xyz123" --window=168h --limit=50`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if healthCodeFlag == "" {
+			fmt.Println("Erreur: Le flag --code est obligatoire")
+			os.Exit(1)
+		}
+
+		db, closeDB := openDatabase()
+		defer closeDB()
+
+		linkService := services.NewLinkService(repository.NewLinkRepository(db))
+		healthService := services.NewHealthService(repository.NewLinkCheckRepository(db))
+
+		link, err := linkService.GetLinkByShortCode(healthCodeFlag)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				fmt.Printf("Erreur: Aucun lien trouvé avec le code '%s'\n", healthCodeFlag)
+			} else {
+				fmt.Printf("Erreur lors de la récupération du lien: %v\n", err)
+			}
+			os.Exit(1)
+		}
+
+		health, err := healthService.GetLinkHealth(link.ID, healthWindowFlag, healthLimitFlag)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidHealthParams) {
+				fmt.Printf("Erreur: Paramètres invalides: %v\n", err)
+			} else {
+				fmt.Printf("Erreur lors de la récupération de l'historique: %v\n", err)
+			}
+			os.Exit(1)
+		}
+
+		fmt.Printf("Santé du lien: %s\n", link.ShortCode)
+		fmt.Printf("URL longue: %s\n", link.LongURL)
+		fmt.Printf("État actuel: %s\n", healthStateLabel(health.State))
+		if health.ConsecutiveFailures > 0 {
+			fmt.Printf("Échecs consécutifs: %d\n", health.ConsecutiveFailures)
+		}
+		if health.LastCheckedAt != nil {
+			fmt.Printf("Dernière vérification: %s\n", health.LastCheckedAt.Local().Format("2006-01-02 15:04:05"))
+		}
+		if health.UptimePercent != nil {
+			fmt.Printf("Disponibilité (%v): %.2f %% sur %d vérification(s)\n", health.Window, *health.UptimePercent, health.Checks)
+		} else {
+			fmt.Printf("Disponibilité (%v): aucune vérification\n", health.Window)
+		}
+
+		if len(health.Recent) == 0 {
+			return
+		}
+		fmt.Println("\nDernières vérifications:")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "DATE\tÉTAT\tMÉTHODE\tCODE HTTP\tLATENCE\tREDIRECTIONS\tERREUR")
+		for _, check := range health.Recent {
+			state := healthStateLabel(services.HealthDown)
+			if check.Up {
+				state = healthStateLabel(services.HealthUp)
+			}
+			statusCode := "-"
+			if check.StatusCode != 0 {
+				statusCode = fmt.Sprint(check.StatusCode)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d ms\t%d\t%s\n", check.CheckedAt.Local().Format("2006-01-02 15:04:05"),
+				state, check.Method, statusCode, check.LatencyMs, len(check.RedirectChain), checkErrorLabel(check))
+		}
+		w.Flush()
+	},
+}
+
+// checkErrorLabel retourne la classe et le détail de l'erreur d'une vérification pour l'affichage.
+func checkErrorLabel(check models.LinkCheck) string {
+	switch {
+	case check.ErrorClass == "":
+		return check.Error
+	case check.Error == "":
+		return check.ErrorClass
+	default:
+		return check.ErrorClass + ": " + check.Error
+	}
+}
+
+// healthStateLabel retourne le libellé d'un état de santé pour l'affichage.
+func healthStateLabel(state string) string {
+	switch state {
+	case services.HealthUp:
+		return "accessible"
+	case services.HealthDown:
+		return "inaccessible"
+	default:
+		return "inconnu (jamais vérifié)"
+	}
+}
+
+func init() {
+	HealthCmd.Flags().StringVarP(&healthCodeFlag, "code", "c", "", "Code court du lien")
+	HealthCmd.Flags().DurationVar(&healthWindowFlag, "window", services.DefaultHealthWindow, "Fenêtre de calcul de la disponibilité (ex: 24h, 168h)")
+	HealthCmd.Flags().IntVarP(&healthLimitFlag, "limit", "l", 10, "Nombre de vérifications affichées")
+	HealthCmd.MarkFlagRequired("code")
+
+	cmd2.RootCmd.AddCommand(HealthCmd)
+}
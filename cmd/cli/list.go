@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+)
+
+// Variables qui stockeront les valeurs des flags de la commande list
+var (
+	listLimitFlag  int
+	listCursorFlag string
+	listSortFlag   string
+	listOrderFlag  string
+	listDomainFlag string
+	listFromFlag   string
+	listToFlag     string
+	listStatusFlag string
+	listFormatFlag string
+)
+
+// listItem est la représentation d'un lien dans la sortie JSON de la commande list.
+type listItem struct {
+	ShortCode    string     `json:"short_code"`
+	LongURL      string     `json:"long_url"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	MaxClicks    int        `json:"max_clicks"`
+	Disabled     bool       `json:"disabled"`
+	Expired      bool       `json:"expired"`
+	TotalClicks  int        `json:"total_clicks"`
+	HealthAction string     `json:"health_action,omitempty"` // Mesure du moniteur pour une destination durablement inaccessible
+}
+
+// ListCmd représente la commande 'list'
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Liste les liens courts avec pagination, tri et filtres.",
+	Long: `Cette commande affiche une page de liens courts sous forme de tableau ou de JSON.
+Le curseur affiché en fin de page permet de récupérer la page suivante avec --cursor.
+
+Exemple:
+  url-shortener list --sort=clicks --limit=10
+  url-shortener list --domain="example.com" --status=active --format=json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if listFormatFlag != "table" && listFormatFlag != "json" {
+			fmt.Printf("Erreur: Format inconnu '%s' (table ou json)\n", listFormatFlag)
+			os.Exit(1)
+		}
+
+		params := services.ListLinksParams{
+			Domain: listDomainFlag,
+			Status: listStatusFlag,
+			SortBy: listSortFlag,
+			Order:  listOrderFlag,
+			Cursor: listCursorFlag,
+			Limit:  listLimitFlag,
+		}
+
+		var err error
+		if params.CreatedFrom, err = parseTimeFlag("from", listFromFlag); err != nil {
+			fmt.Printf("Erreur: %v\n", err)
+			os.Exit(1)
+		}
+		if params.CreatedTo, err = parseTimeFlag("to", listToFlag); err != nil {
+			fmt.Printf("Erreur: %v\n", err)
+			os.Exit(1)
+		}
+
+		db, closeDB := openDatabase()
+		defer closeDB()
+
+		linkService := services.NewLinkService(repository.NewLinkRepository(db))
+
+		page, err := linkService.ListLinks(params)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidListParams) {
+				fmt.Printf("Erreur: Paramètres invalides: %v\n", err)
+			} else {
+				fmt.Printf("Erreur lors de la récupération des liens: %v\n", err)
+			}
+			os.Exit(1)
+		}
+
+		now := time.Now()
+		items := make([]listItem, 0, len(page.Links))
+		for _, summary := range page.Links {
+			items = append(items, listItem{
+				ShortCode:    summary.ShortCode,
+				LongURL:      summary.LongURL,
+				CreatedAt:    summary.CreatedAt,
+				ExpiresAt:    summary.ExpiresAt,
+				MaxClicks:    summary.MaxClicks,
+				Disabled:     summary.Disabled,
+				Expired:      summary.HasEnded(now, summary.ClickCount),
+				TotalClicks:  summary.ClickCount,
+				HealthAction: summary.HealthAction,
+			})
+		}
+
+		if listFormatFlag == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			encoder.Encode(map[string]any{"links": items, "next_cursor": page.NextCursor})
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CODE\tCLICS\tSTATUT\tCRÉÉ LE\tURL LONGUE")
+		for _, item := range items {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", item.ShortCode, item.TotalClicks, itemStatus(item),
+				item.CreatedAt.Format("2006-01-02 15:04"), item.LongURL)
+		}
+		w.Flush()
+
+		if page.NextCursor != "" {
+			fmt.Printf("\nPage suivante: --cursor=%s\n", page.NextCursor)
+		}
+	},
+}
+
+// itemStatus retourne le statut lisible d'un lien pour l'affichage en tableau.
+func itemStatus(item listItem) string {
+	switch {
+	case item.Disabled:
+		return "désactivé"
+	case item.Expired:
+		return "expiré"
+	case item.HealthAction == models.HealthActionDisable:
+		return "suspendu"
+	case item.HealthAction == models.HealthActionFallback:
+		return "repli"
+	default:
+		return "actif"
+	}
+}
+
+// parseTimeFlag parse un flag de date optionnel au format RFC 3339.
+func parseTimeFlag(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("date invalide pour --%s '%s' (format attendu RFC 3339)", name, value)
+	}
+	return &t, nil
+}
+
+func init() {
+	ListCmd.Flags().IntVarP(&listLimitFlag, "limit", "l", services.DefaultListLimit, "Nombre de liens par page")
+	ListCmd.Flags().StringVar(&listCursorFlag, "cursor", "", "Curseur de la page à afficher")
+	ListCmd.Flags().StringVar(&listSortFlag, "sort", "created_at", "Critère de tri (created_at ou clicks)")
+	ListCmd.Flags().StringVar(&listOrderFlag, "order", "desc", "Ordre de tri (asc ou desc)")
+	ListCmd.Flags().StringVar(&listDomainFlag, "domain", "", "Filtre sur le domaine de destination")
+	ListCmd.Flags().StringVar(&listFromFlag, "from", "", "Date de création minimale (RFC 3339)")
+	ListCmd.Flags().StringVar(&listToFlag, "to", "", "Date de création maximale (RFC 3339)")
+	ListCmd.Flags().StringVar(&listStatusFlag, "status", "", "Filtre sur le statut (active, disabled ou expired)")
+	ListCmd.Flags().StringVarP(&listFormatFlag, "format", "f", "table", "Format de sortie (table ou json)")
+
+	cmd2.RootCmd.AddCommand(ListCmd)
+}
@@ -1,57 +1,171 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
-	"github.com/axellelanca/urlshortener/internal/models"
-	"log"
+	"os"
+	"text/tabwriter"
+	"time"
 
 	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/migrations"
 	"github.com/spf13/cobra"
+)
 
-	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
+// Variables qui stockeront les valeurs des flags des commandes migrate
+var (
+	migrateStepsFlag int
+	migrateDirFlag   string
 )
 
 // MigrateCmd représente la commande 'migrate'
 var MigrateCmd = &cobra.Command{
 	Use:   "migrate",
 	Short: "Exécute les migrations de la base de données pour créer ou mettre à jour les tables.",
-	Long: `Cette commande se connecte à la base de données configurée (SQLite)
-et exécute les migrations automatiques de GORM pour créer les tables 'links' et 'clicks'
-basées sur les modèles Go.`,
+	Long: `Cette commande se connecte à la base de données configurée (SQLite, PostgreSQL ou MySQL)
+et applique les migrations versionnées en attente. L'historique des migrations appliquées
+est conservé dans la table 'schema_migrations'.
+
+Sans sous-commande, 'migrate' équivaut à 'migrate up'.
+
+Exemple:
+  url-shortener migrate status
+  url-shortener migrate down --steps=1
+  url-shortener migrate create add_link_tags`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Charger la configuration chargée globalement via cmd.cfg
-		cfg := cmd2.Cfg
-		if cfg == nil {
-			log.Fatal("FATAL: Configuration non initialisée")
+		migrateUp()
+	},
+}
+
+// MigrateUpCmd représente la commande 'migrate up'
+var MigrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Applique toutes les migrations en attente.",
+	Run: func(cmd *cobra.Command, args []string) {
+		migrateUp()
+	},
+}
+
+// MigrateDownCmd représente la commande 'migrate down'
+var MigrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Annule les dernières migrations appliquées.",
+	Long: `Cette commande annule les migrations appliquées, de la plus récente à la plus ancienne.
+Attention : annuler la migration initiale supprime toutes les tables et leurs données.
+
+Exemple:
+  url-shortener migrate down --steps=2`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if migrateStepsFlag < 1 {
+			fmt.Println("Erreur: --steps doit être supérieur ou égal à 1")
+			os.Exit(1)
 		}
 
-		// Initialiser la connexion à la base de données SQLite avec GORM.
-		db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{})
+		db, closeDB := openDatabase()
+		defer closeDB()
+
+		done, err := migrations.NewMigrator(db).Down(migrateStepsFlag)
+		for _, migration := range done {
+			fmt.Printf("Annulée: %s_%s\n", migration.Version, migration.Name)
+		}
 		if err != nil {
-			log.Fatalf("FATAL: Impossible de se connecter à la base de données: %v", err)
+			if errors.Is(err, migrations.ErrUnknownMigration) {
+				fmt.Printf("Erreur: %v (utilisez le binaire qui l'a appliquée)\n", err)
+			} else {
+				fmt.Printf("Erreur lors de l'annulation des migrations: %v\n", err)
+			}
+			os.Exit(1)
+		}
+		if len(done) == 0 {
+			fmt.Println("Aucune migration à annuler.")
 		}
+	},
+}
 
-		sqlDB, err := db.DB()
+// MigrateStatusCmd représente la commande 'migrate status'
+var MigrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Affiche l'état de chaque migration.",
+	Run: func(cmd *cobra.Command, args []string) {
+		db, closeDB := openDatabase()
+		defer closeDB()
+
+		statuses, err := migrations.NewMigrator(db).Status()
 		if err != nil {
-			log.Fatalf("FATAL: Échec de l'obtention de la base de données SQL sous-jacente: %v", err)
+			fmt.Printf("Erreur lors de la lecture de l'historique des migrations: %v\n", err)
+			os.Exit(1)
 		}
-		// Assurez-vous que la connexion est fermée après la migration.
-		defer sqlDB.Close()
 
-		// Exécuter les migrations automatiques de GORM.
-		// Utilisez db.AutoMigrate() et passez-lui les pointeurs vers tous vos modèles.
-		err = db.AutoMigrate(&models.Link{}, &models.Click{})
-		if err != nil {
-			log.Fatalf("FATAL: Échec de la migration automatique: %v", err)
+		pending := 0
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNOM\tÉTAT\tAPPLIQUÉE LE")
+		for _, status := range statuses {
+			state := "appliquée"
+			switch {
+			case status.Unknown:
+				state = "inconnue de ce binaire"
+			case status.AppliedAt == nil:
+				state = "en attente"
+				pending++
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", status.Version, status.Name, state, formatOptionalTime(status.AppliedAt))
+		}
+		w.Flush()
+
+		if pending > 0 {
+			fmt.Printf("\n%d migration(s) en attente : exécutez 'url-shortener migrate up'.\n", pending)
 		}
+	},
+}
+
+// MigrateCreateCmd représente la commande 'migrate create'
+var MigrateCreateCmd = &cobra.Command{
+	Use:   "create <nom>",
+	Short: "Génère le squelette d'une nouvelle migration.",
+	Long: `Cette commande crée un fichier Go de migration versionné par la date courante (UTC),
+avec des étapes Up et Down à compléter. La migration est prise en compte à la
+prochaine compilation du binaire.
 
-		// Pas touche au log
-		fmt.Println("Migrations de la base de données exécutées avec succès.")
+Exemple:
+  url-shortener migrate create add_link_tags`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := migrations.Create(migrateDirFlag, args[0], time.Now())
+		if err != nil {
+			if errors.Is(err, migrations.ErrInvalidMigrationName) {
+				fmt.Printf("Erreur: %v\n", err)
+			} else {
+				fmt.Printf("Erreur lors de la création de la migration: %v\n", err)
+			}
+			os.Exit(1)
+		}
+		fmt.Printf("Migration créée: %s\n", path)
 	},
 }
 
+// migrateUp applique les migrations en attente et affiche celles qui l'ont été.
+func migrateUp() {
+	db, closeDB := openDatabase()
+	defer closeDB()
+
+	done, err := migrations.NewMigrator(db).Up()
+	for _, migration := range done {
+		fmt.Printf("Appliquée: %s_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		fmt.Printf("Erreur lors de l'application des migrations: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Pas touche au log
+	fmt.Println("Migrations de la base de données exécutées avec succès.")
+}
+
 func init() {
+	MigrateDownCmd.Flags().IntVar(&migrateStepsFlag, "steps", 1, "Nombre de migrations à annuler")
+	MigrateCreateCmd.Flags().StringVar(&migrateDirFlag, "dir", "internal/migrations", "Répertoire des fichiers de migration")
+
 	// Ajouter la commande à RootCmd
+	MigrateCmd.AddCommand(MigrateUpCmd, MigrateDownCmd, MigrateStatusCmd, MigrateCreateCmd)
 	cmd2.RootCmd.AddCommand(MigrateCmd)
 }
@@ -5,19 +5,29 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"text/tabwriter"
+	"time"
 
 	cmd2 "github.com/axellelanca/urlshortener/cmd"
 	"github.com/axellelanca/urlshortener/internal/repository"
 	"github.com/axellelanca/urlshortener/internal/services"
 	"github.com/spf13/cobra"
 
-	"gorm.io/driver/sqlite" // Driver SQLite pour GORM
 	"gorm.io/gorm"
 )
 
 // variable shortCodeFlag qui stockera la valeur du flag --code
 var shortCodeFlag string
 
+// Variables qui stockeront les valeurs des flags du mode série temporelle
+var (
+	statsFromFlag     string
+	statsToFlag       string
+	statsIntervalFlag string
+	statsTZFlag       string
+	statsTopFlag      int
+)
+
 // StatsCmd représente la commande 'stats'
 var StatsCmd = &cobra.Command{
 	Use:   "stats",
@@ -25,8 +35,13 @@ var StatsCmd = &cobra.Command{
 	Long: `Cette commande permet de récupérer et d'afficher le nombre total de clics
 pour une URL courte spécifique en utilisant son code.
 
+Les principales sources de trafic (referrers, navigateurs, systèmes, appareils)
+sont affichées selon --top. Avec --from, --to ou --interval, les clics sont également affichés par période
+(hour, day ou week) dans le fuseau horaire --tz.
+
 Exemple:
-  url-shortener stats --code="xyz123"`, Run: func(cmd *cobra.Command, args []string) {
+  url-shortener stats --code="xyz123"
+  url-shortener stats --code="xyz123" --interval=day --from="2025-03-01T00:00:00Z" --tz="Europe/Paris"`, Run: func(cmd *cobra.Command, args []string) {
 		// Valider que le flag --code a été fourni.
 		if shortCodeFlag == "" {
 			fmt.Println("Erreur: Le flag --code est obligatoire")
@@ -39,8 +54,8 @@ Exemple:
 			log.Fatal("FATAL: Configuration non initialisée")
 		}
 
-		// Initialiser la connexion à la base de données SQLite avec GORM.
-		db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{})
+		// Initialiser la connexion à la base de données configurée avec GORM.
+		db, err := cmd2.OpenDatabase(cfg)
 		if err != nil {
 			log.Fatalf("FATAL: Impossible de se connecter à la base de données: %v", err)
 		}
@@ -73,14 +88,121 @@ Exemple:
 		fmt.Printf("Statistiques pour le code court: %s\n", link.ShortCode)
 		fmt.Printf("URL longue: %s\n", link.LongURL)
 		fmt.Printf("Total de clics: %d\n", totalClicks)
+
+		clickService := services.NewClickService(repository.NewClickRepository(db))
+		uniqueVisitors, err := clickService.CountUniqueVisitors(link.ID)
+		if err != nil {
+			fmt.Printf("Erreur lors du comptage des visiteurs uniques: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Visiteurs uniques (estimation): %d\n", uniqueVisitors)
+		if link.ExpiresAt != nil {
+			fmt.Printf("Expire le: %s\n", link.ExpiresAt.Format(time.RFC3339))
+		}
+		if link.MaxClicks > 0 {
+			fmt.Printf("Clics restants: %d/%d\n", link.RemainingClicks(totalClicks), link.MaxClicks)
+		}
+		if link.Disabled {
+			fmt.Println("Statut: DÉSACTIVÉ")
+		} else if link.HasEnded(time.Now(), totalClicks) {
+			fmt.Println("Statut: EXPIRÉ")
+		}
+
+		if statsTopFlag > 0 {
+			printBreakdowns(clickService, link.ID, statsTopFlag)
+		}
+
+		// Mode série temporelle, activé par l'un des flags --from, --to ou --interval.
+		if !cmd.Flags().Changed("from") && !cmd.Flags().Changed("to") && !cmd.Flags().Changed("interval") {
+			return
+		}
+		printTimeSeries(clickService, link.ID)
 	},
 }
 
+// printBreakdowns affiche les top N referrers, navigateurs, systèmes et appareils d'un lien.
+func printBreakdowns(clickService *services.ClickService, linkID uint, top int) {
+	breakdowns, err := clickService.GetClickBreakdowns(linkID, top)
+	if err != nil {
+		fmt.Printf("Erreur lors de la récupération des répartitions: %v\n", err)
+		os.Exit(1)
+	}
+
+	sections := []struct {
+		title   string
+		entries []repository.BreakdownEntry
+	}{
+		{"Referrers", breakdowns.Referrers},
+		{"Navigateurs", breakdowns.Browsers},
+		{"Systèmes", breakdowns.OperatingSystems},
+		{"Appareils", breakdowns.Devices},
+	}
+	for _, section := range sections {
+		if len(section.entries) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", section.title)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, entry := range section.entries {
+			fmt.Fprintf(w, "  %s\t%d\n", entry.Value, entry.Clicks)
+		}
+		w.Flush()
+	}
+}
+
+// printTimeSeries affiche les clics d'un lien regroupés par période selon les flags de la commande stats.
+func printTimeSeries(clickService *services.ClickService, linkID uint) {
+	loc, err := time.LoadLocation(statsTZFlag)
+	if err != nil {
+		fmt.Printf("Erreur: Fuseau horaire invalide '%s': %v\n", statsTZFlag, err)
+		os.Exit(1)
+	}
+
+	to := time.Now()
+	if parsed, err := parseTimeFlag("to", statsToFlag); err != nil {
+		fmt.Printf("Erreur: %v\n", err)
+		os.Exit(1)
+	} else if parsed != nil {
+		to = *parsed
+	}
+	from := to.Add(-services.DefaultTimeSeriesSpan(statsIntervalFlag))
+	if parsed, err := parseTimeFlag("from", statsFromFlag); err != nil {
+		fmt.Printf("Erreur: %v\n", err)
+		os.Exit(1)
+	} else if parsed != nil {
+		from = *parsed
+	}
+
+	buckets, err := clickService.GetClickTimeSeries(linkID, from, to, statsIntervalFlag, loc)
+	if err != nil {
+		fmt.Printf("Erreur lors de la récupération de la série temporelle: %v\n", err)
+		os.Exit(1)
+	}
+
+	layout := "2006-01-02 15:04 MST"
+	if statsIntervalFlag != services.IntervalHour {
+		layout = "2006-01-02"
+	}
+
+	fmt.Printf("\nClics par période (%s, %s):\n", statsIntervalFlag, loc)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DÉBUT\tCLICS")
+	for _, bucket := range buckets {
+		fmt.Fprintf(w, "%s\t%d\n", bucket.Start.Format(layout), bucket.Clicks)
+	}
+	w.Flush()
+}
+
 // init() s'exécute automatiquement lors de l'importation du package.
 // Il est utilisé pour définir les flags que cette commande accepte.
 func init() {
 	// Définir le flag --code pour la commande stats.
 	StatsCmd.Flags().StringVarP(&shortCodeFlag, "code", "c", "", "Code court du lien pour lequel afficher les statistiques")
+	StatsCmd.Flags().StringVar(&statsFromFlag, "from", "", "Début de la série temporelle (RFC 3339)")
+	StatsCmd.Flags().StringVar(&statsToFlag, "to", "", "Fin de la série temporelle (RFC 3339, défaut maintenant)")
+	StatsCmd.Flags().StringVar(&statsIntervalFlag, "interval", services.IntervalDay, "Granularité de la série temporelle (hour, day ou week)")
+	StatsCmd.Flags().StringVar(&statsTZFlag, "tz", "UTC", "Fuseau horaire IANA utilisé pour aligner les périodes")
+	StatsCmd.Flags().IntVar(&statsTopFlag, "top", 5, "Nombre d'entrées par répartition (referrers, navigateurs...), 0 pour masquer")
 
 	// Marquer le flag comme requis
 	StatsCmd.MarkFlagRequired("code")
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// Variables qui stockeront les valeurs des flags --code, --url, --fallback-url et --redirect-type de la commande update
+var (
+	updateCodeFlag         string
+	updateURLFlag          string
+	updateFallbackURLFlag  string
+	updateRedirectTypeFlag int
+)
+
+// UpdateCmd représente la commande 'update'
+var UpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Modifie l'URL de destination, l'URL de repli ou le type de redirection d'un lien court.",
+	Long: `Cette commande remplace l'URL longue, l'URL de repli et/ou le type de redirection associés à un code court existant.
+Une URL de repli vide (--fallback-url="") retire l'URL de repli du lien.
+
+Exemple:
+  url-shortener update --code="xyz123" --url="https://www.example.com/nouvelle-page"
+  url-shortener update --code="xyz123" --fallback-url="https://www.example.com"
+  url-shortener update --code="xyz123" --redirect-type=308`,
+	Run: func(cmd *cobra.Command, args []string) {
+		urlChanged := cmd.Flags().Changed("url")
+		fallbackChanged := cmd.Flags().Changed("fallback-url")
+		redirectTypeChanged := cmd.Flags().Changed("redirect-type")
+		if updateCodeFlag == "" || !urlChanged && !fallbackChanged && !redirectTypeChanged {
+			fmt.Println("Erreur: Le flag --code et au moins un des flags --url, --fallback-url ou --redirect-type sont obligatoires")
+			os.Exit(1)
+		}
+
+		var opts services.UpdateLinkOptions
+		if urlChanged {
+			if _, err := url.ParseRequestURI(updateURLFlag); err != nil {
+				fmt.Printf("Erreur: URL invalide '%s': %v\n", updateURLFlag, err)
+				os.Exit(1)
+			}
+			opts.LongURL = &updateURLFlag
+		}
+		if fallbackChanged {
+			if updateFallbackURLFlag != "" {
+				if _, err := url.ParseRequestURI(updateFallbackURLFlag); err != nil {
+					fmt.Printf("Erreur: URL de repli invalide '%s': %v\n", updateFallbackURLFlag, err)
+					os.Exit(1)
+				}
+			}
+			opts.FallbackURL = &updateFallbackURLFlag
+		}
+		if redirectTypeChanged {
+			if err := services.ValidateRedirectType(updateRedirectTypeFlag); err != nil {
+				fmt.Printf("Erreur: Type de redirection invalide: %v\n", err)
+				os.Exit(1)
+			}
+			opts.RedirectType = &updateRedirectTypeFlag
+		}
+
+		db, closeDB := openDatabase()
+		defer closeDB()
+
+		linkService := services.NewLinkService(repository.NewLinkRepository(db))
+		linkService.SetAuditActor(models.AuditActorCLI)
+		urlValidator, _, err := cmd2.NewURLValidator(cmd2.Cfg)
+		if err != nil {
+			fmt.Printf("Erreur: Impossible de charger la liste de domaines bloqués: %v\n", err)
+			os.Exit(1)
+		}
+		linkService.SetURLValidator(urlValidator)
+
+		link, err := linkService.UpdateLink(updateCodeFlag, opts)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				fmt.Printf("Erreur: Aucun lien trouvé avec le code '%s'\n", updateCodeFlag)
+			} else {
+				fmt.Printf("Erreur lors de la modification du lien: %v\n", err)
+			}
+			os.Exit(1)
+		}
+
+		fmt.Printf("Lien %s mis à jour avec succès.\n", link.ShortCode)
+		fmt.Printf("URL longue: %s\n", link.LongURL)
+		if link.FallbackURL != "" {
+			fmt.Printf("URL de repli: %s\n", link.FallbackURL)
+		}
+		fmt.Printf("Redirection: %d\n", link.RedirectType)
+	},
+}
+
+func init() {
+	UpdateCmd.Flags().StringVarP(&updateCodeFlag, "code", "c", "", "Code court du lien à modifier")
+	UpdateCmd.Flags().StringVarP(&updateURLFlag, "url", "u", "", "Nouvelle URL longue de destination")
+	UpdateCmd.Flags().StringVar(&updateFallbackURLFlag, "fallback-url", "", "Nouvelle URL de repli (vide pour la retirer)")
+	UpdateCmd.Flags().IntVar(&updateRedirectTypeFlag, "redirect-type", 0, "Nouveau code de redirection : 301, 302, 307 ou 308")
+	UpdateCmd.MarkFlagRequired("code")
+
+	cmd2.RootCmd.AddCommand(UpdateCmd)
+}
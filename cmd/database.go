@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/config"
+	"github.com/axellelanca/urlshortener/internal/logging"
+	"gorm.io/driver/mysql"    // Driver MySQL pour GORM
+	"gorm.io/driver/postgres" // Driver PostgreSQL pour GORM
+	"gorm.io/driver/sqlite"   // Driver SQLite pour GORM
+	"gorm.io/gorm"
+)
+
+// Moteurs de base de données acceptés par database.driver.
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+	DriverMemory   = "memory" // Stockage en mémoire du serveur, perdu à l'arrêt (démonstration, tests)
+)
+
+var (
+	// ErrUnknownDriver est retournée quand database.driver ne désigne aucun moteur connu.
+	ErrUnknownDriver = errors.New("unknown database driver")
+	// ErrMissingDSN est retournée quand un moteur serveur est configuré sans chaîne de connexion.
+	ErrMissingDSN = errors.New("database dsn is required for this driver")
+	// ErrMemoryDriver est retournée quand une commande autre que run-server doit ouvrir
+	// la base alors que le stockage en mémoire est configuré : ses données seraient perdues.
+	ErrMemoryDriver = errors.New("the memory driver has no database to open, only run-server can use it")
+)
+
+// OpenDatabase ouvre la connexion à la base de données décrite par la configuration
+// et applique les réglages du pool de connexions. C'est le point d'entrée unique
+// des commandes qui accèdent à la base.
+func OpenDatabase(cfg *config.Config) (*gorm.DB, error) {
+	dialector, err := newDialector(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logging.NewGormLogger(),
+		// Les violations de contrainte d'unicité sont traduites en gorm.ErrDuplicatedKey
+		// quel que soit le moteur.
+		TranslateError: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.Database.ConnMaxLifetimeMinutes) * time.Minute)
+	sqlDB.SetConnMaxIdleTime(time.Duration(cfg.Database.ConnMaxIdleTimeMinutes) * time.Minute)
+
+	return db, nil
+}
+
+// newDialector choisit le driver GORM correspondant à database.driver.
+func newDialector(cfg *config.Config) (gorm.Dialector, error) {
+	switch cfg.Database.Driver {
+	case DriverSQLite, "":
+		return sqlite.Open(cfg.Database.Name), nil
+	case DriverPostgres, DriverMySQL:
+		if cfg.Database.DSN == "" {
+			return nil, fmt.Errorf("%w: %s", ErrMissingDSN, cfg.Database.Driver)
+		}
+		if cfg.Database.Driver == DriverPostgres {
+			return postgres.Open(cfg.Database.DSN), nil
+		}
+		return mysql.Open(cfg.Database.DSN), nil
+	case DriverMemory:
+		return nil, ErrMemoryDriver
+	default:
+		return nil, fmt.Errorf("%w %q (expected %s, %s, %s or %s)", ErrUnknownDriver, cfg.Database.Driver,
+			DriverSQLite, DriverPostgres, DriverMySQL, DriverMemory)
+	}
+}
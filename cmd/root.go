@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 
 	"github.com/axellelanca/urlshortener/internal/config"
+	"github.com/axellelanca/urlshortener/internal/logging"
 	"github.com/spf13/cobra"
 )
 
@@ -68,12 +71,31 @@ func init() {
 func initConfig() {
 	var err error
 	Cfg, err = config.LoadConfig()
+	if errors.Is(err, config.ErrInvalidConfig) {
+		// Une valeur refusée n'est pas remplacée en silence : mieux vaut s'arrêter tout de suite
+		// que de démarrer avec une configuration différente de celle demandée.
+		log.Fatalf("FATAL: Configuration invalide: %v", err)
+	}
 	if err != nil {
 		// Loggue l'erreur mais ne fait pas un os.Exit(1) ici si LoadConfig()
 		// gère déjà l'absence de fichier avec des valeurs par défaut.
 		// Si LoadConfig() termine le programme en cas d'erreur fatale,
 		// cette vérification est surtout pour les avertissements.
 		log.Printf("Attention: Problème lors du chargement de la configuration: %v. Utilisation des valeurs par défaut.", err)
+		return
+	}
+
+	// Installer le logger structuré configuré ; en cas d'erreur on garde le logger par défaut.
+	if err := logging.Setup(Cfg.Log.Level, Cfg.Log.Format); err != nil {
+		log.Printf("Attention: Configuration des logs invalide: %v. Utilisation du logger par défaut.", err)
 	}
+
+	// Log pour vérifier la config chargée, émis une fois le logger configuré.
+	slog.Info("Configuration chargée",
+		"port", Cfg.Server.Port,
+		"database_driver", Cfg.Database.Driver,
+		"database", Cfg.Database.Name,
+		"analytics_buffer", Cfg.Analytics.BufferSize,
+		"monitor_interval_minutes", Cfg.Monitor.IntervalMinutes)
 	// La configuration est maintenant disponible via la variable globale 'cmd.cfg'.
 }
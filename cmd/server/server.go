@@ -1,28 +1,37 @@
 package server
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	cmd2 "github.com/axellelanca/urlshortener/cmd"
 	"github.com/axellelanca/urlshortener/internal/api"
+	"github.com/axellelanca/urlshortener/internal/config"
+	"github.com/axellelanca/urlshortener/internal/metrics"
+	"github.com/axellelanca/urlshortener/internal/migrations"
 	"github.com/axellelanca/urlshortener/internal/models"
 	"github.com/axellelanca/urlshortener/internal/monitor"
+	"github.com/axellelanca/urlshortener/internal/notify"
+	"github.com/axellelanca/urlshortener/internal/ratelimit"
 	"github.com/axellelanca/urlshortener/internal/repository"
 	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/axellelanca/urlshortener/internal/spill"
 	"github.com/axellelanca/urlshortener/internal/workers"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cobra"
-	"gorm.io/driver/sqlite" // Driver SQLite pour GORM
-	"gorm.io/gorm"
 )
 
+// autoMigrateFlag applique les migrations en attente au démarrage du serveur.
+var autoMigrateFlag bool
+
 // RunServerCmd représente la commande 'run-server' de Cobra.
 // C'est le point d'entrée pour lancer le serveur de l'application.
 var RunServerCmd = &cobra.Command{
@@ -35,63 +44,176 @@ puis lance le serveur HTTP.`,
 		// Charger la configuration chargée globalement via cmd.cfg
 		cfg := cmd2.Cfg
 		if cfg == nil {
-			log.Fatal("FATAL: Configuration non initialisée")
+			fatal("Configuration non initialisée", nil)
 		}
 
-		// Initialiser la connexion à la base de données SQLite avec GORM.
-		// Utilisez le nom de la base de données depuis la configuration (cfg.Database.Name).
-		db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{})
-		if err != nil {
-			log.Fatalf("FATAL: Impossible de se connecter à la base de données: %v", err)
-		}
-
-		// Effectuer les migrations automatiques pour créer les tables
-		err = db.AutoMigrate(&models.Link{}, &models.Click{})
-		if err != nil {
-			log.Fatalf("FATAL: Impossible d'effectuer les migrations de base de données: %v", err)
+		// Initialiser le stockage configuré (database.driver) et les repositories.
+		store := openStorage(cfg)
+		linkRepo := store.links
+		if cacheCfg := cfg.Links.Cache; cacheCfg.Enabled {
+			cachedLinks := repository.NewCachedLinkRepository(linkRepo, repository.LinkCacheConfig{
+				MaxEntries:  cacheCfg.MaxEntries,
+				TTL:         time.Duration(cacheCfg.TTLSeconds) * time.Second,
+				NegativeTTL: time.Duration(cacheCfg.NegativeTTLSeconds) * time.Second,
+			})
+			metrics.RegisterLinkCache(cachedLinks.Len)
+			linkRepo = cachedLinks
+			slog.Info("Cache des liens activé.", "max_entries", cacheCfg.MaxEntries,
+				"ttl_seconds", cacheCfg.TTLSeconds, "negative_ttl_seconds", cacheCfg.NegativeTTLSeconds)
 		}
-		log.Println("Migrations de base de données effectuées avec succès.")
-
-		// Initialiser les repositories.
-		// Créez des instances de GormLinkRepository et GormClickRepository.
-		linkRepo := repository.NewLinkRepository(db)
-		clickRepo := repository.NewClickRepository(db)
+		clickRepo := store.clicks
+		apiKeyRepo := store.apiKeys
+		linkCheckRepo := store.linkChecks
+		linkAuditRepo := store.linkAudit
 
 		// Laissez le log
-		log.Println("Repositories initialisés.")
+		slog.Info("Repositories initialisés.")
 
 		// Initialiser les services métiers.
 		// Créez des instances de LinkService et ClickService, en leur passant les repositories nécessaires.
 		linkService := services.NewLinkService(linkRepo)
+		urlValidator, blocklist, err := cmd2.NewURLValidator(cfg)
+		if err != nil {
+			fatal("Impossible de charger la liste de domaines bloqués", err)
+		}
+		linkService.SetURLValidator(urlValidator)
+		if fallbackURL := cfg.Monitor.DeadLink.FallbackURL; fallbackURL != "" {
+			if err := urlValidator.Validate(fallbackURL); err != nil {
+				fatal("URL de repli invalide (monitor.dead_link.fallback_url)", err)
+			}
+			linkService.SetUnreachableFallbackURL(fallbackURL)
+		}
+		if err := services.ValidateRedirectType(cfg.Links.DefaultRedirectType); err != nil {
+			fatal("Type de redirection par défaut invalide (links.default_redirect_type)", err)
+		}
+		linkService.SetDefaultRedirectType(cfg.Links.DefaultRedirectType)
+		linkService.SetPermanentRedirectMaxAge(time.Duration(cfg.Links.PermanentRedirectMaxAgeSeconds) * time.Second)
+		clickService := services.NewClickService(clickRepo)
+		healthService := services.NewHealthService(linkCheckRepo)
+		auditService := services.NewAuditService(linkAuditRepo)
+
+		// Un service de clés nil désactive l'authentification de l'API de gestion.
+		var apiKeyService *services.APIKeyService
+		if cfg.Auth.Enabled {
+			apiKeyService = services.NewAPIKeyService(apiKeyRepo)
+			if cfg.Database.Driver == cmd2.DriverMemory {
+				createEphemeralAPIKey(apiKeyService)
+			} else {
+				warnIfNoActiveAPIKey(apiKeyService)
+			}
+		} else {
+			slog.Warn("Authentification de l'API désactivée (auth.enabled=false) : /api/v1 est accessible sans clé")
+		}
 
 		// Laissez le log
-		log.Println("Services métiers initialisés.")
+		slog.Info("Services métiers initialisés.")
 
 		// Initialiser le channel ClickEventsChannel (api/handlers) des événements de clic et lancer les workers (StartClickWorkers).
 		// Le channel est bufferisé avec la taille configurée.
 		// Passez le channel et le clickRepo aux workers.
 		clickEventsChannel := make(chan models.ClickEvent, cfg.Analytics.BufferSize)
 		api.ClickEventsChannel = clickEventsChannel
-		workers.StartClickWorkers(cfg.Analytics.WorkerCount, clickEventsChannel, clickRepo)
+		metrics.RegisterClickChannel(clickEventsChannel)
+		if cfg.Analytics.VisitorSalt == "" {
+			slog.Warn("analytics.visitor_salt n'est pas configuré, les empreintes de visiteurs ne sont pas salées")
+		}
+		workerConfig := workers.ClickWorkerConfig{
+			WorkerCount:   cfg.Analytics.WorkerCount,
+			BatchSize:     cfg.Analytics.BatchSize,
+			FlushInterval: time.Duration(cfg.Analytics.FlushIntervalMs) * time.Millisecond,
+			MaxRetries:    cfg.Analytics.MaxRetries,
+			RetryBackoff:  time.Duration(cfg.Analytics.RetryBackoffMs) * time.Millisecond,
+			VisitorSalt:   cfg.Analytics.VisitorSalt,
+		}
+		// Les workers s'arrêtent normalement à la fermeture du channel ; l'annulation de ce
+		// contexte les arrête sans le fermer, si des handlers peuvent encore y écrire.
+		workersCtx, stopClickWorkers := context.WithCancel(context.Background())
+		defer stopClickWorkers()
+		clickWorkers := workers.StartClickWorkers(workersCtx, workerConfig, clickEventsChannel, clickRepo)
+
+		slog.Info("Channel d'événements de clic initialisé, workers de clics démarrés",
+			"buffer_size", cfg.Analytics.BufferSize, "workers", cfg.Analytics.WorkerCount)
 
-		log.Printf("Channel d'événements de clic initialisé avec un buffer de %d. %d worker(s) de clics démarré(s).",
-			cfg.Analytics.BufferSize, cfg.Analytics.WorkerCount)
+		// Les tâches de fond (moniteur, sweeper) s'arrêtent à l'annulation de ce contexte.
+		backgroundCtx, stopBackground := context.WithCancel(context.Background())
+		defer stopBackground()
+		var backgroundTasks sync.WaitGroup
 
 		// Initialiser et lancer le moniteur d'URLs.
 		// Utilisez l'intervalle configuré (cfg.Monitor.IntervalMinutes).
 		// Lancez le moniteur dans sa propre goroutine.
-		monitorInterval := time.Duration(cfg.Monitor.IntervalMinutes) * time.Minute
-		urlMonitor := monitor.NewUrlMonitor(linkRepo, monitorInterval) // Le moniteur a besoin du linkRepo et de l'interval
-		go urlMonitor.Start()
-		log.Printf("Moniteur d'URLs démarré avec un intervalle de %v.", monitorInterval)
+		monitorConfig := newMonitorConfig(cfg)
+		notifier, notificationQueue := newNotifier(cfg)
+		if notificationQueue != nil {
+			backgroundTasks.Add(1)
+			go func() {
+				defer backgroundTasks.Done()
+				notificationQueue.Run(backgroundCtx)
+			}()
+		}
+		urlMonitor := monitor.NewUrlMonitor(linkRepo, linkCheckRepo, notifier, monitorConfig) // Le moniteur a besoin des repositories et de l'interval
+		backgroundTasks.Add(1)
+		go func() {
+			defer backgroundTasks.Done()
+			urlMonitor.Start(backgroundCtx)
+		}()
+		slog.Info("Moniteur d'URLs démarré", "interval", monitorConfig.Interval.String())
+
+		// Lancer le sweeper qui marque les liens expirés pour les exclure du moniteur.
+		sweepInterval := time.Duration(cfg.Links.SweepIntervalMinutes) * time.Minute
+		expirationSweeper := workers.NewExpirationSweeper(linkRepo, sweepInterval)
+		backgroundTasks.Add(1)
+		go func() {
+			defer backgroundTasks.Done()
+			expirationSweeper.Start(backgroundCtx)
+		}()
+
+		// Recharger à chaud la liste de domaines bloqués quand le fichier change.
+		if blocklist != nil && cfg.URLSafety.BlocklistReloadSeconds > 0 {
+			reloadInterval := time.Duration(cfg.URLSafety.BlocklistReloadSeconds) * time.Second
+			backgroundTasks.Add(1)
+			go func() {
+				defer backgroundTasks.Done()
+				blocklist.Start(backgroundCtx, reloadInterval)
+			}()
+			slog.Info("Liste de domaines bloqués chargée", "path", cfg.URLSafety.BlocklistFile, "domains", blocklist.Len())
+		}
+
+		// Ouvrir le journal de débordement des clics et lancer sa relecture.
+		var spillLog *spill.Log
+		if cfg.Analytics.Spill.Enabled {
+			spillLog, err = spill.Open(cfg.Analytics.Spill.Dir, cfg.Analytics.Spill.SegmentMaxBytes)
+			if err != nil {
+				fatal("Impossible d'ouvrir le journal de débordement des clics", err)
+			}
+			api.ClickSpillLog = spillLog
+
+			replayInterval := time.Duration(cfg.Analytics.Spill.ReplayIntervalSeconds) * time.Second
+			spillReplayer := workers.NewSpillReplayer(spillLog, clickEventsChannel, clickRepo, workerConfig, replayInterval)
+			backgroundTasks.Add(1)
+			go func() {
+				defer backgroundTasks.Done()
+				spillReplayer.Start(backgroundCtx)
+			}()
+			slog.Info("Débordement des clics sur disque activé", "dir", cfg.Analytics.Spill.Dir)
+		}
 
 		// Configurer le routeur Gin et les handlers API.
 		// Passez les services nécessaires aux fonctions de configuration des routes.
-		router := gin.Default()
-		api.SetupRoutes(router, linkService, cfg.Analytics.BufferSize, cfg.Server.BaseURL)
+		// Le logger texte de gin.Default est remplacé par un journal structuré des requêtes,
+		// corrélé par X-Request-ID.
+		// Les messages de debug de Gin (liste des routes) ne sont pas structurés :
+		// ils ne sont affichés qu'au niveau debug, sauf si GIN_MODE est défini.
+		if os.Getenv(gin.EnvGinMode) == "" && cfg.Log.Level != "debug" {
+			gin.SetMode(gin.ReleaseMode)
+		}
+		limiters := newRateLimiters(cfg)
+		router := gin.New()
+		router.Use(gin.Recovery(), api.RequestIDMiddleware(), api.RequestLoggerMiddleware())
+		api.SetupRoutes(router, linkService, clickService, healthService, auditService, apiKeyService, limiters, cfg.Analytics.BufferSize, cfg.Server.BaseURL, cfg.Links.FallbackURL)
 
 		// Pas toucher au log
-		log.Println("Routes API configurées.")
+		slog.Info("Routes API configurées.")
 
 		// Créer le serveur HTTP Gin
 		serverAddr := fmt.Sprintf(":%d", cfg.Server.Port)
@@ -103,10 +225,9 @@ puis lance le serveur HTTP.`,
 		// Démarrer le serveur Gin dans une goroutine anonyme pour ne pas bloquer.
 		// Pensez à logger des ptites informations...
 		go func() {
-			log.Printf("Serveur HTTP démarré sur le port %d", cfg.Server.Port)
-			log.Printf("URL de base: %s", cfg.Server.BaseURL)
+			slog.Info("Serveur HTTP démarré", "port", cfg.Server.Port, "base_url", cfg.Server.BaseURL)
 			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-				log.Fatalf("FATAL: Erreur lors du démarrage du serveur: %v", err)
+				fatal("Erreur lors du démarrage du serveur", err)
 			}
 		}()
 
@@ -117,17 +238,274 @@ puis lance le serveur HTTP.`,
 
 		// Bloquer jusqu'à ce qu'un signal d'arrêt soit reçu.
 		<-quit
-		log.Println("Signal d'arrêt reçu. Arrêt du serveur...")
+		slog.Info("Signal d'arrêt reçu. Arrêt du serveur...")
 
-		// Arrêt propre du serveur HTTP avec un timeout.
-		log.Println("Arrêt en cours... Donnez un peu de temps aux workers pour finir.")
-		time.Sleep(5 * time.Second)
+		// L'ensemble de l'arrêt est borné par le timeout configuré.
+		shutdownTimeout := time.Duration(cfg.Server.ShutdownTimeoutSeconds) * time.Second
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
 
-		log.Println("Serveur arrêté proprement.")
+		// 1. Ne plus accepter de requêtes et attendre la fin des requêtes en cours.
+		// Si Shutdown expire, Close coupe les connexions mais des handlers peuvent encore
+		// s'exécuter : ils risquent d'écrire dans le channel et le journal de débordement.
+		httpStopped := true
+		if err := srv.Shutdown(ctx); err != nil {
+			slog.Warn("Arrêt du serveur HTTP incomplet, des requêtes sont peut-être encore en cours", "error", err)
+			srv.Close()
+			httpStopped = false
+		}
+		slog.Info("Serveur HTTP arrêté.")
+
+		// 2. Arrêter le moniteur, le sweeper et la relecture des clics débordés.
+		stopBackground()
+
+		// 3. Arrêter les workers de clics.
+		if httpStopped {
+			// Plus aucun handler ne peut écrire dans le channel : le fermer laisse les workers
+			// écrire tous les événements restants puis s'arrêter.
+			close(clickEventsChannel)
+			slog.Info("Vidange du channel de clics", "pending_events", len(clickEventsChannel))
+		} else {
+			// Le channel reste ouvert (un handler pourrait encore y écrire) : les workers
+			// écrivent leur lot en cours et s'arrêtent sans vider le channel.
+			stopClickWorkers()
+			slog.Warn("Arrêt des workers de clics sans vidange du channel", "pending_events", len(clickEventsChannel))
+		}
+
+		// 4. Attendre les workers et les tâches de fond, dans la limite du timeout.
+		workersStopped := waitWithContext(ctx, clickWorkers)
+		if workersStopped && httpStopped {
+			slog.Info("Workers de clics arrêtés, tous les événements ont été enregistrés.")
+		} else if !workersStopped {
+			slog.Warn("Timeout atteint, événements de clic non enregistrés", "pending_events", len(clickEventsChannel))
+		}
+		backgroundStopped := waitWithContext(ctx, &backgroundTasks)
+		if !backgroundStopped {
+			slog.Warn("Timeout atteint avant l'arrêt des tâches de fond")
+		}
+
+		// 5. Fermer le journal de débordement et la base, seulement si plus rien ne peut s'en
+		// servir ; sinon le processus se termine en les laissant ouverts.
+		if !httpStopped || !workersStopped || !backgroundStopped {
+			slog.Warn("Arrêt incomplet : journal de débordement et base de données laissés ouverts")
+			return
+		}
+
+		// Les segments non relus seront repris au prochain démarrage.
+		if spillLog != nil {
+			if err := spillLog.Close(); err != nil {
+				slog.Warn("Erreur lors de la fermeture du journal de débordement", "error", err)
+			}
+		}
+
+		// Fermer la connexion à la base de données.
+		if err := store.close(); err != nil {
+			slog.Warn("Erreur lors de la fermeture de la base de données", "error", err)
+		}
+
+		slog.Info("Serveur arrêté proprement.")
 	},
 }
 
+// storage regroupe les repositories du serveur et la fermeture du stockage sous-jacent.
+type storage struct {
+	links      repository.LinkRepository
+	clicks     repository.ClickRepository
+	apiKeys    repository.APIKeyRepository
+	linkChecks repository.LinkCheckRepository
+	linkAudit  repository.LinkAuditRepository
+	close      func() error
+}
+
+// openStorage ouvre le stockage configuré : la base de données, dont le schéma doit être
+// à jour, ou un stockage en mémoire pour database.driver: memory.
+func openStorage(cfg *config.Config) storage {
+	if cfg.Database.Driver == cmd2.DriverMemory {
+		slog.Warn("Stockage en mémoire (database.driver=memory) : toutes les données seront perdues à l'arrêt du serveur")
+		memory := repository.NewMemoryStore()
+		return storage{
+			links:      repository.NewMemoryLinkRepository(memory),
+			clicks:     repository.NewMemoryClickRepository(memory),
+			apiKeys:    repository.NewMemoryAPIKeyRepository(memory),
+			linkChecks: repository.NewMemoryLinkCheckRepository(memory),
+			linkAudit:  repository.NewMemoryLinkAuditRepository(memory),
+			close:      func() error { return nil },
+		}
+	}
+
+	// Initialiser la connexion à la base de données configurée avec GORM.
+	db, err := cmd2.OpenDatabase(cfg)
+	if err != nil {
+		fatal("Impossible de se connecter à la base de données", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		fatal("Échec de l'obtention de la base de données SQL sous-jacente", err)
+	}
+
+	// Vérifier que le schéma est à jour : les migrations ne sont appliquées au démarrage
+	// que si --auto-migrate est demandé, sinon le serveur refuse de démarrer.
+	migrator := migrations.NewMigrator(db)
+	if autoMigrateFlag {
+		applied, err := migrator.Up()
+		if err != nil {
+			fatal("Impossible d'effectuer les migrations de base de données", err)
+		}
+		slog.Info("Migrations de base de données effectuées avec succès.", "applied", len(applied))
+	} else if err := migrator.CheckCurrent(); err != nil {
+		fatal("Schéma de base de données non à jour : exécutez 'url-shortener migrate up' ou démarrez avec --auto-migrate", err)
+	}
+
+	return storage{
+		links:      repository.NewLinkRepository(db),
+		clicks:     repository.NewClickRepository(db),
+		apiKeys:    repository.NewAPIKeyRepository(db),
+		linkChecks: repository.NewLinkCheckRepository(db),
+		linkAudit:  repository.NewLinkAuditRepository(db),
+		close:      sqlDB.Close,
+	}
+}
+
+// newMonitorConfig traduit la configuration du moniteur, dont le budget de vérifications par hôte.
+func newMonitorConfig(cfg *config.Config) monitor.Config {
+	if cfg.Monitor.JitterPercent < 0 || cfg.Monitor.JitterPercent > 100 {
+		fatal("Étalement du moniteur invalide (monitor.jitter_percent)",
+			fmt.Errorf("jitter_percent must be between 0 and 100, got %d", cfg.Monitor.JitterPercent))
+	}
+	monitorConfig := monitor.Config{
+		Interval:         time.Duration(cfg.Monitor.IntervalMinutes) * time.Minute,
+		Retention:        time.Duration(cfg.Monitor.RetentionDays) * 24 * time.Hour,
+		Concurrency:      cfg.Monitor.Concurrency,
+		Jitter:           float64(cfg.Monitor.JitterPercent) / 100,
+		FailureThreshold: cfg.Monitor.FailureThreshold,
+		UserAgent:        cfg.Monitor.UserAgent,
+		DeadLink: monitor.DeadLinkPolicy{
+			After:       time.Duration(cfg.Monitor.DeadLink.AfterHours) * time.Hour,
+			FallbackURL: cfg.Monitor.DeadLink.FallbackURL,
+		},
+	}
+	switch cfg.Monitor.DeadLink.Action {
+	case "none", "":
+	case models.HealthActionDisable, models.HealthActionFallback:
+		monitorConfig.DeadLink.Action = cfg.Monitor.DeadLink.Action
+	default:
+		fatal("Politique des liens inaccessibles invalide (monitor.dead_link.action)",
+			fmt.Errorf("action must be none, disable or fallback, got %q", cfg.Monitor.DeadLink.Action))
+	}
+	if cfg.Monitor.PerHostRequestsPerMinute > 0 {
+		limiter, err := ratelimit.NewLimiter("monitor_host",
+			ratelimit.PerMinute(cfg.Monitor.PerHostRequestsPerMinute, cfg.Monitor.PerHostBurst), ratelimit.NewMemoryStore())
+		if err != nil {
+			fatal("Budget de vérifications par hôte invalide (monitor.per_host_*)", err)
+		}
+		monitorConfig.HostLimiter = limiter
+	}
+	return monitorConfig
+}
+
+// newNotifier construit les canaux de notification des changements d'état : les logs,
+// plus un webhook par destinataire configuré. Les webhooks sont livrés en arrière-plan
+// via la file retournée, à lancer avec Run ; elle est nil sans webhook configuré.
+func newNotifier(cfg *config.Config) (notify.Notifier, *notify.Queue) {
+	logNotifier := notify.NewLogNotifier()
+	var webhooks notify.Multi
+	for _, webhook := range cfg.Notifications.Webhooks {
+		if webhook.Secret == "" {
+			slog.Warn("Webhook configuré sans secret : les livraisons ne seront pas signées", "url", webhook.URL)
+		}
+		webhooks = append(webhooks, notify.NewWebhookNotifier(notify.WebhookConfig{
+			URL:          webhook.URL,
+			Secret:       webhook.Secret,
+			Timeout:      time.Duration(cfg.Notifications.TimeoutSeconds) * time.Second,
+			MaxRetries:   cfg.Notifications.MaxRetries,
+			RetryBackoff: time.Duration(cfg.Notifications.RetryBackoffMs) * time.Millisecond,
+		}, nil))
+	}
+	slog.Info("Notifications configurées", "webhooks", len(cfg.Notifications.Webhooks))
+	if len(webhooks) == 0 {
+		return logNotifier, nil
+	}
+	queue := notify.NewQueue(webhooks, cfg.Notifications.QueueSize)
+	return notify.Multi{logNotifier, queue}, queue
+}
+
+// newRateLimiters construit les budgets de requêtes configurés, partageant un même store en mémoire.
+func newRateLimiters(cfg *config.Config) api.RateLimiters {
+	if !cfg.RateLimit.Enabled {
+		slog.Warn("Limitation de débit désactivée (rate_limit.enabled=false)")
+		return api.RateLimiters{}
+	}
+
+	store := ratelimit.NewMemoryStore()
+	create, err := ratelimit.NewLimiter("create",
+		ratelimit.PerMinute(cfg.RateLimit.Create.RequestsPerMinute, cfg.RateLimit.Create.Burst), store)
+	if err != nil {
+		fatal("Budget de création de liens invalide (rate_limit.create)", err)
+	}
+	redirect, err := ratelimit.NewLimiter("redirect",
+		ratelimit.PerMinute(cfg.RateLimit.Redirect.RequestsPerMinute, cfg.RateLimit.Redirect.Burst), store)
+	if err != nil {
+		fatal("Budget de redirections invalide (rate_limit.redirect)", err)
+	}
+	return api.RateLimiters{Create: create, Redirect: redirect}
+}
+
+// warnIfNoActiveAPIKey signale au démarrage qu'aucune clé ne permet d'utiliser l'API de gestion.
+func warnIfNoActiveAPIKey(apiKeyService *services.APIKeyService) {
+	keys, err := apiKeyService.ListAPIKeys()
+	if err != nil {
+		slog.Warn("Impossible de lister les clés d'API", "error", err)
+		return
+	}
+	for _, key := range keys {
+		if !key.IsRevoked() {
+			return
+		}
+	}
+	slog.Warn("Aucune clé d'API active : créez-en une avec 'url-shortener apikey create'")
+}
+
+// createEphemeralAPIKey crée une clé d'API temporaire disposant de toutes les portées :
+// avec le stockage en mémoire, la commande 'apikey create' ne peut pas en enregistrer.
+func createEphemeralAPIKey(apiKeyService *services.APIKeyService) {
+	raw, _, err := apiKeyService.CreateAPIKey("ephemeral", models.AllScopes)
+	if err != nil {
+		fatal("Impossible de créer la clé d'API temporaire", err)
+	}
+	slog.Warn("Clé d'API temporaire créée pour le stockage en mémoire, valable jusqu'à l'arrêt du serveur", "api_key", raw)
+}
+
+// fatal journalise une erreur fatale de démarrage puis termine le programme.
+func fatal(msg string, err error) {
+	if err != nil {
+		slog.Error("FATAL: "+msg, "error", err)
+	} else {
+		slog.Error("FATAL: " + msg)
+	}
+	os.Exit(1)
+}
+
+// waitWithContext attend la fin du WaitGroup ou l'expiration du contexte.
+// Elle retourne false si le contexte a expiré avant.
+func waitWithContext(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 func init() {
+	RunServerCmd.Flags().BoolVar(&autoMigrateFlag, "auto-migrate", false, "Applique les migrations en attente au démarrage au lieu de refuser de démarrer")
+
 	// ajouter la commande
 	cmd2.RootCmd.AddCommand(RunServerCmd)
 }
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"net/url"
+
+	"github.com/axellelanca/urlshortener/internal/config"
+	"github.com/axellelanca/urlshortener/internal/urlsafety"
+)
+
+// NewURLValidator construit la validation des URLs de destination à partir de la configuration.
+// La liste de blocage retournée est nil si aucun fichier n'est configuré ; le serveur
+// l'utilise pour la recharger à chaud.
+func NewURLValidator(cfg *config.Config) (*urlsafety.Validator, *urlsafety.Blocklist, error) {
+	opts := urlsafety.Options{
+		AllowedSchemes: cfg.URLSafety.AllowedSchemes,
+		AllowPrivate:   !cfg.URLSafety.BlockPrivateTargets,
+		ResolveHosts:   cfg.URLSafety.ResolveHosts,
+	}
+
+	// Un lien vers le service lui-même redirigerait en boucle.
+	if base, err := url.Parse(cfg.Server.BaseURL); err == nil && base.Host != "" {
+		opts.SelfHosts = []string{base.Host}
+	}
+
+	if cfg.URLSafety.BlocklistFile != "" {
+		blocklist, err := urlsafety.LoadBlocklist(cfg.URLSafety.BlocklistFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts.Blocklist = blocklist
+	}
+
+	return urlsafety.NewValidator(opts), opts.Blocklist, nil
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader est l'en-tête alternatif à "Authorization: Bearer" pour présenter une clé d'API.
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyContextKey est la clé du contexte Gin sous laquelle la clé authentifiée est stockée.
+const apiKeyContextKey = "api_key"
+
+// APIKeyAuthMiddleware exige une clé d'API valide, présentée dans l'en-tête
+// "Authorization: Bearer <clé>" ou "X-API-Key". Si apiKeyService est nil,
+// l'authentification est désactivée et toutes les requêtes sont acceptées.
+func APIKeyAuthMiddleware(apiKeyService *services.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if apiKeyService == nil {
+			c.Next()
+			return
+		}
+
+		raw := extractAPIKey(c)
+		if raw == "" {
+			c.Header("WWW-Authenticate", `Bearer realm="api"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key required"})
+			return
+		}
+
+		key, err := apiKeyService.Authenticate(raw)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidAPIKey) {
+				c.Header("WWW-Authenticate", `Bearer realm="api", error="invalid_token"`)
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				return
+			}
+			requestLogger(c).Error("Erreur lors de l'authentification de la clé d'API", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		c.Set(apiKeyContextKey, key)
+		c.Next()
+	}
+}
+
+// RequireScope refuse la requête (403) si la clé authentifiée ne dispose pas de la portée demandée.
+// Sans clé dans le contexte (authentification désactivée), la requête est acceptée.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := authenticatedKey(c)
+		if key != nil && !key.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key is missing scope " + scope})
+			return
+		}
+		c.Next()
+	}
+}
+
+// authenticatedKey retourne la clé d'API authentifiée de la requête, nil sinon.
+func authenticatedKey(c *gin.Context) *models.APIKey {
+	value, ok := c.Get(apiKeyContextKey)
+	if !ok {
+		return nil
+	}
+	key, _ := value.(*models.APIKey)
+	return key
+}
+
+// extractAPIKey lit la clé d'API présentée par le client, vide si absente.
+func extractAPIKey(c *gin.Context) string {
+	if auth := c.GetHeader("Authorization"); auth != "" {
+		scheme, token, found := strings.Cut(auth, " ")
+		if found && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	return strings.TrimSpace(c.GetHeader(APIKeyHeader))
+}
@@ -1,14 +1,19 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
-	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/axellelanca/urlshortener/internal/metrics"
 	"github.com/axellelanca/urlshortener/internal/models"
 	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/axellelanca/urlshortener/internal/spill"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"gorm.io/gorm" // Pour gérer gorm.ErrRecordNotFound
 )
 
@@ -16,8 +21,17 @@ import (
 // aux workers asynchrones. Il est bufferisé pour ne pas bloquer les requêtes de redirection.
 var ClickEventsChannel chan models.ClickEvent
 
-// SetupRoutes configure toutes les routes de l'API Gin et injecte les dépendances nécessaires
-func SetupRoutes(router *gin.Engine, linkService *services.LinkService, bufferSize int, baseURL string) {
+// ClickSpillLog est le journal sur disque optionnel qui reçoit les événements de clic
+// quand ClickEventsChannel est plein. Nil si le débordement sur disque est désactivé.
+var ClickSpillLog *spill.Log
+
+// SetupRoutes configure toutes les routes de l'API Gin et injecte les dépendances nécessaires.
+// Les routes /api/v1 exigent une clé d'API portant la portée adéquate ; passer un
+// apiKeyService nil désactive cette authentification. La redirection reste publique.
+// La création de liens et les redirections sont soumises aux budgets de limiters.
+func SetupRoutes(router *gin.Engine, linkService *services.LinkService, clickService *services.ClickService,
+	healthService *services.HealthService, auditService *services.AuditService, apiKeyService *services.APIKeyService, limiters RateLimiters,
+	bufferSize int, baseURL string, fallbackURL string) {
 	// Le channel est initialisé ici.
 	if ClickEventsChannel == nil {
 		// Créer le channel ici (make), il doit être bufférisé
@@ -28,18 +42,39 @@ func SetupRoutes(router *gin.Engine, linkService *services.LinkService, bufferSi
 	// Route de Health Check , /health
 	router.GET("/health", HealthCheckHandler)
 
+	// Route des métriques Prometheus, /metrics
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	// Routes de l'API
 	// Doivent être au format /api/v1/
 	// POST /links
 	// GET /links/:shortCode/stats
-	api := router.Group("/api/v1")
+	// GET /links/:shortCode/stats/timeseries
+	// GET /links/:shortCode/health
+	// GET /links/:shortCode/audit
+	// GET /links
+	// PATCH /links/:shortCode
+	// DELETE /links/:shortCode
+	api := router.Group("/api/v1", APIKeyAuthMiddleware(apiKeyService))
 	{
-		api.POST("/links", CreateShortLinkHandler(linkService, baseURL))
-		api.GET("/links/:shortCode/stats", GetLinkStatsHandler(linkService))
+		api.POST("/links", RequireScope(models.ScopeLinksWrite), RateLimitMiddleware(limiters.Create),
+			CreateShortLinkHandler(linkService, baseURL))
+		api.GET("/links", RequireScope(models.ScopeLinksRead), ListLinksHandler(linkService, baseURL))
+		api.PATCH("/links/:shortCode", RequireScope(models.ScopeLinksWrite), UpdateLinkHandler(linkService, baseURL))
+		api.DELETE("/links/:shortCode", RequireScope(models.ScopeLinksWrite), DeleteLinkHandler(linkService))
+		api.GET("/links/:shortCode/stats", RequireScope(models.ScopeStatsRead), GetLinkStatsHandler(linkService, clickService))
+		api.GET("/links/:shortCode/stats/timeseries", RequireScope(models.ScopeStatsRead), GetLinkTimeSeriesHandler(linkService, clickService))
+		api.GET("/links/:shortCode/health", RequireScope(models.ScopeStatsRead), GetLinkHealthHandler(linkService, healthService))
+		api.GET("/links/:shortCode/audit", RequireScope(models.ScopeLinksRead), GetLinkAuditHandler(linkService, auditService))
 	}
 
-	// Route de Redirection (au niveau racine pour les short codes)
-	router.GET("/:shortCode", RedirectHandler(linkService))
+	// Route de Redirection (au niveau racine pour les short codes).
+	// Les méthodes avec corps ne sont acceptées que pour les liens en 307/308, qui les conservent
+	// (voir RedirectHandler).
+	rateLimit, redirect := RateLimitMiddleware(limiters.Redirect), RedirectHandler(linkService, fallbackURL)
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		router.Handle(method, "/:shortCode", rateLimit, redirect)
+	}
 }
 
 // HealthCheckHandler gère la route /health pour vérifier l'état du service.
@@ -50,7 +85,12 @@ func HealthCheckHandler(c *gin.Context) {
 
 // CreateLinkRequest représente le corps de la requête JSON pour la création d'un lien.
 type CreateLinkRequest struct {
-	LongURL string `json:"long_url" binding:"required,url"` // 'binding:required' pour validation, 'url' pour format URL
+	LongURL      string     `json:"long_url" binding:"required,url"`      // 'binding:required' pour validation, 'url' pour format URL
+	CustomAlias  string     `json:"custom_alias"`                         // Alias personnalisé optionnel (ex: "spring-sale")
+	ExpiresAt    *time.Time `json:"expires_at"`                           // Date d'expiration optionnelle au format RFC 3339
+	MaxClicks    int        `json:"max_clicks" binding:"min=0"`           // Budget de clics optionnel (0 = illimité)
+	FallbackURL  string     `json:"fallback_url" binding:"omitempty,url"` // URL de repli si la destination devient durablement inaccessible
+	RedirectType int        `json:"redirect_type"`                        // Code de redirection optionnel : 301, 302, 307 ou 308
 }
 
 // CreateShortLinkHandler gère la création d'une URL courte.
@@ -65,25 +105,206 @@ func CreateShortLinkHandler(linkService *services.LinkService, baseURL string) g
 		}
 
 		// Appeler le LinkService (CreateLink pour créer le nouveau lien.
-		link, err := linkService.CreateLink(req.LongURL)
+		link, err := linkService.CreateLink(req.LongURL, services.CreateLinkOptions{
+			CustomAlias: req.CustomAlias,
+			ExpiresAt:   req.ExpiresAt,
+			MaxClicks:   req.MaxClicks,
+			FallbackURL: req.FallbackURL,
+
+			RedirectType: req.RedirectType,
+		})
 		if err != nil {
-			log.Printf("Error creating link: %v", err)
+			// Les erreurs de validation de l'alias sont renvoyées telles quelles au client.
+			switch {
+			case errors.Is(err, services.ErrAliasTaken):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			case errors.Is(err, services.ErrInvalidAlias), errors.Is(err, services.ErrReservedAlias),
+				errors.Is(err, services.ErrInvalidExpiration), errors.Is(err, services.ErrUnsafeURL),
+				errors.Is(err, services.ErrInvalidRedirectType):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			requestLogger(c).Error("Erreur lors de la création du lien", "error", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create link"})
 			return
 		}
 
+		metrics.LinksCreated.Inc()
+
 		// Retourne le code court et l'URL longue dans la réponse JSON.
-		c.JSON(http.StatusCreated, gin.H{
-			"short_code":     link.ShortCode,
-			"long_url":       link.LongURL,
-			"full_short_url": baseURL + "/" + link.ShortCode,
+		c.JSON(http.StatusCreated, linkResponse(link, baseURL))
+	}
+}
+
+// linkResponse construit la représentation JSON d'un lien renvoyée par l'API de gestion.
+func linkResponse(link *models.Link, baseURL string) gin.H {
+	return gin.H{
+		"short_code":     link.ShortCode,
+		"long_url":       link.LongURL,
+		"full_short_url": baseURL + "/" + link.ShortCode,
+		"expires_at":     link.ExpiresAt,
+		"max_clicks":     link.MaxClicks,
+		"disabled":       link.Disabled,
+		"fallback_url":   link.FallbackURL,
+		"redirect_type":  link.RedirectType,
+		"health_action":  link.HealthAction,
+		"down_since":     link.DownSince,
+		"created_at":     link.CreatedAt,
+	}
+}
+
+// ListLinksHandler gère le listing paginé des liens.
+// Paramètres de requête : limit, cursor, sort (created_at|clicks), order (asc|desc),
+// domain, created_from, created_to (RFC 3339) et status (active|disabled|expired).
+func ListLinksHandler(linkService *services.LinkService, baseURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		params := services.ListLinksParams{
+			Domain: c.Query("domain"),
+			Status: c.Query("status"),
+			SortBy: c.Query("sort"),
+			Order:  c.Query("order"),
+			Cursor: c.Query("cursor"),
+		}
+
+		if raw := c.Query("limit"); raw != "" {
+			limit, err := strconv.Atoi(raw)
+			if err != nil || limit <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+				return
+			}
+			params.Limit = limit
+		}
+
+		var err error
+		if params.CreatedFrom, err = parseTimeQuery(c, "created_from"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if params.CreatedTo, err = parseTimeQuery(c, "created_to"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		page, err := linkService.ListLinks(params)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidListParams) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			requestLogger(c).Error("Erreur lors de la liste des liens", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		now := time.Now()
+		items := make([]gin.H, 0, len(page.Links))
+		for _, summary := range page.Links {
+			item := linkResponse(&summary.Link, baseURL)
+			item["total_clicks"] = summary.ClickCount
+			item["expired"] = summary.HasEnded(now, summary.ClickCount)
+			items = append(items, item)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"links":       items,
+			"next_cursor": page.NextCursor,
 		})
 	}
 }
 
+// parseTimeQuery lit un paramètre de requête optionnel au format RFC 3339.
+func parseTimeQuery(c *gin.Context, key string) (*time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, errors.New(key + " must be an RFC 3339 date")
+	}
+	return &t, nil
+}
+
+// UpdateLinkRequest représente le corps de la requête JSON pour la modification d'un lien.
+// Seuls les champs présents sont modifiés.
+type UpdateLinkRequest struct {
+	LongURL      *string `json:"long_url" binding:"omitempty,url"` // Nouvelle URL de destination
+	Disabled     *bool   `json:"disabled"`                         // Active (false) ou désactive (true) la redirection
+	FallbackURL  *string `json:"fallback_url"`                     // Nouvelle URL de repli, "" pour la retirer
+	RedirectType *int    `json:"redirect_type"`                    // Nouveau code de redirection : 301, 302, 307 ou 308
+}
+
+// UpdateLinkHandler gère la modification de la destination ou de l'état d'un lien.
+func UpdateLinkHandler(linkService *services.LinkService, baseURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shortCode := c.Param("shortCode")
+
+		var req UpdateLinkRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.LongURL == nil && req.Disabled == nil && req.FallbackURL == nil && req.RedirectType == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Nothing to update"})
+			return
+		}
+
+		link, err := linkService.UpdateLink(shortCode, services.UpdateLinkOptions{
+			LongURL:     req.LongURL,
+			Disabled:    req.Disabled,
+			FallbackURL: req.FallbackURL,
+
+			RedirectType: req.RedirectType,
+		})
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Short URL not found"})
+				return
+			}
+			if errors.Is(err, services.ErrUnsafeURL) || errors.Is(err, services.ErrInvalidRedirectType) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			requestLogger(c).Error("Erreur lors de la modification du lien", "short_code", shortCode, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update link"})
+			return
+		}
+
+		c.JSON(http.StatusOK, linkResponse(link, baseURL))
+	}
+}
+
+// DeleteLinkHandler gère la suppression logique d'un lien.
+func DeleteLinkHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shortCode := c.Param("shortCode")
+
+		if err := linkService.DeleteLink(shortCode); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Short URL not found"})
+				return
+			}
+			requestLogger(c).Error("Erreur lors de la suppression du lien", "short_code", shortCode, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete link"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
 // RedirectHandler gère la redirection d'une URL courte vers l'URL longue et l'enregistrement asynchrone des clics.
-func RedirectHandler(linkService *services.LinkService) gin.HandlerFunc {
+// Un lien désactivé, expiré ou dont le budget de clics est épuisé répond 410 Gone,
+// accompagné de fallbackURL si elle est configurée.
+func RedirectHandler(linkService *services.LinkService, fallbackURL string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// La durée est observée une fois la réponse écrite, quel que soit son statut.
+		start := time.Now()
+		defer func() {
+			metrics.RedirectDuration.WithLabelValues(strconv.Itoa(c.Writer.Status())).Observe(time.Since(start).Seconds())
+		}()
+
 		// Récupère le shortCode de l'URL avec c.Param
 		shortCode := c.Param("shortCode")
 
@@ -97,7 +318,35 @@ func RedirectHandler(linkService *services.LinkService) gin.HandlerFunc {
 				return
 			}
 			// Gérer d'autres erreurs potentielles de la base de données ou du service
-			log.Printf("Error retrieving link for %s: %v", shortCode, err)
+			requestLogger(c).Error("Erreur lors de la récupération du lien", "short_code", shortCode, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		// Une redirection 301/302 serait suivie en GET : la requête d'origine n'atteindrait pas
+		// la destination. Elle est refusée avant de compter un clic ou d'entamer le budget.
+		if c.Request.Method != http.MethodGet && !services.PreservesRequestMethod(linkService.RedirectStatus(link)) {
+			c.Header("Allow", http.MethodGet)
+			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Method not allowed for this short URL"})
+			return
+		}
+
+		// Vérifier que le lien n'est pas désactivé ni expiré, puis réserver un clic sur son budget.
+		err = linkService.CheckLinkAvailability(link)
+		if err == nil {
+			err = linkService.ReserveClick(link)
+		}
+		if err != nil {
+			if errors.Is(err, services.ErrLinkDisabled) || errors.Is(err, services.ErrLinkExpired) ||
+				errors.Is(err, services.ErrClickBudgetExhausted) || errors.Is(err, services.ErrLinkUnreachable) {
+				body := gin.H{"error": err.Error()}
+				if fallbackURL != "" {
+					body["fallback_url"] = fallbackURL
+				}
+				c.JSON(http.StatusGone, body)
+				return
+			}
+			requestLogger(c).Error("Erreur lors de la vérification de la disponibilité du lien", "short_code", shortCode, "error", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 			return
 		}
@@ -108,6 +357,8 @@ func RedirectHandler(linkService *services.LinkService) gin.HandlerFunc {
 			Timestamp: time.Now(),
 			UserAgent: c.GetHeader("User-Agent"),
 			IPAddress: c.ClientIP(),
+			Referrer:  c.GetHeader("Referer"),
+			RequestID: requestID(c),
 		}
 
 		// Envoyer le ClickEvent dans le ClickEventsChannel avec le Multiplexage.
@@ -116,21 +367,70 @@ func RedirectHandler(linkService *services.LinkService) gin.HandlerFunc {
 		case ClickEventsChannel <- clickEvent:
 			// Succès: l'événement a été envoyé
 		default:
-			// Pour le default, juste un message à afficher :
-			log.Printf("Warning: ClickEventsChannel is full, dropping click event for %s.", shortCode)
+			// Channel plein : mettre l'événement de côté sur disque s'il est configuré,
+			// les workers le reliront quand la pression sera retombée.
+			spillClickEvent(clickEvent, shortCode)
 		}
 
-		// Effectuer la redirection HTTP 302 (StatusFound) vers l'URL longue.
-		c.Redirect(http.StatusFound, link.LongURL)
+		// Seules les redirections permanentes peuvent être mises en cache par les clients ;
+		// les autres doivent revenir au serveur à chaque clic pour être comptées.
+		if maxAge := linkService.RedirectCacheMaxAge(link, time.Now()); maxAge >= time.Second {
+			c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge.Seconds())))
+		} else {
+			c.Header("Cache-Control", "no-store")
+		}
+
+		// Effectuer la redirection HTTP (code propre au lien) vers l'URL longue,
+		// ou vers l'URL de repli si le moniteur a basculé le lien.
+		c.Redirect(linkService.RedirectStatus(link), linkService.DestinationURL(link))
+	}
+}
+
+// Bornes du paramètre 'top' des répartitions de clics.
+const (
+	defaultBreakdownTop = 5
+	maxBreakdownTop     = 50
+)
+
+// spillClickEvent écrit un événement de clic dans ClickSpillLog, ou le perd si le
+// débordement sur disque est désactivé ou en échec.
+func spillClickEvent(event models.ClickEvent, shortCode string) {
+	logger := slog.With("request_id", event.RequestID, "short_code", shortCode)
+	if ClickSpillLog == nil {
+		metrics.ClicksDropped.WithLabelValues("channel_full").Inc()
+		logger.Warn("Channel de clics plein, événement de clic abandonné")
+		return
+	}
+	payload, err := json.Marshal(event)
+	if err == nil {
+		err = ClickSpillLog.Append(payload)
 	}
+	if err != nil {
+		metrics.ClicksDropped.WithLabelValues("spill_failed").Inc()
+		logger.Error("Channel de clics plein et débordement sur disque en échec, événement de clic abandonné", "error", err)
+		return
+	}
+	metrics.ClicksSpilled.Inc()
+	logger.Debug("Channel de clics plein, événement de clic mis de côté sur disque")
 }
 
 // GetLinkStatsHandler gère la récupération des statistiques pour un lien spécifique.
-func GetLinkStatsHandler(linkService *services.LinkService) gin.HandlerFunc {
+// Le paramètre de requête optionnel 'top' fixe le nombre d'entrées par répartition (défaut 5).
+func GetLinkStatsHandler(linkService *services.LinkService, clickService *services.ClickService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Récupère le shortCode de l'URL avec c.Param
 		shortCode := c.Param("shortCode")
 
+		top := defaultBreakdownTop
+		if raw := c.Query("top"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n <= 0 || n > maxBreakdownTop {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "top must be an integer between 1 and 50"})
+				return
+			}
+			top = n
+		}
+
 		// Appeler le LinkService pour obtenir le lien et le nombre total de clics.
 		link, totalClicks, err := linkService.GetLinkStats(shortCode)
 		if err != nil {
@@ -141,16 +441,243 @@ func GetLinkStatsHandler(linkService *services.LinkService) gin.HandlerFunc {
 				return
 			}
 			// Gérer d'autres erreurs
-			log.Printf("Error retrieving stats for %s: %v", shortCode, err)
+			requestLogger(c).Error("Erreur lors de la récupération des statistiques", "short_code", shortCode, "error", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 			return
 		}
 
 		// Retourne les statistiques dans la réponse JSON.
-		c.JSON(http.StatusOK, gin.H{
+		response := gin.H{
 			"short_code":   link.ShortCode,
 			"long_url":     link.LongURL,
 			"total_clicks": totalClicks,
+			"expires_at":   link.ExpiresAt,
+			"max_clicks":   link.MaxClicks,
+			"disabled":     link.Disabled,
+			"expired":      link.HasEnded(time.Now(), totalClicks),
+		}
+		if link.MaxClicks > 0 {
+			response["remaining_clicks"] = link.RemainingClicks(totalClicks)
+		}
+
+		uniqueVisitors, err := clickService.CountUniqueVisitors(link.ID)
+		if err != nil {
+			requestLogger(c).Error("Erreur lors du comptage des visiteurs uniques", "short_code", shortCode, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+		response["unique_visitors"] = uniqueVisitors
+
+		breakdowns, err := clickService.GetClickBreakdowns(link.ID, top)
+		if err != nil {
+			requestLogger(c).Error("Erreur lors de la récupération des répartitions", "short_code", shortCode, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+		response["breakdowns"] = breakdowns
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// GetLinkTimeSeriesHandler gère la récupération des clics d'un lien regroupés par période.
+// Paramètres de requête : from, to (RFC 3339), interval (hour|day|week, défaut day)
+// et tz (fuseau IANA, ex: Europe/Paris, défaut UTC).
+func GetLinkTimeSeriesHandler(linkService *services.LinkService, clickService *services.ClickService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shortCode := c.Param("shortCode")
+
+		interval := c.DefaultQuery("interval", services.IntervalDay)
+
+		loc, err := time.LoadLocation(c.DefaultQuery("tz", "UTC"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "tz must be a valid IANA time zone"})
+			return
+		}
+
+		to := time.Now()
+		if parsed, err := parseTimeQuery(c, "to"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		} else if parsed != nil {
+			to = *parsed
+		}
+		from := to.Add(-services.DefaultTimeSeriesSpan(interval))
+		if parsed, err := parseTimeQuery(c, "from"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		} else if parsed != nil {
+			from = *parsed
+		}
+
+		link, err := linkService.GetLinkByShortCode(shortCode)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Short URL not found"})
+				return
+			}
+			requestLogger(c).Error("Erreur lors de la récupération du lien", "short_code", shortCode, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		buckets, err := clickService.GetClickTimeSeries(link.ID, from, to, interval, loc)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidTimeSeries) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			requestLogger(c).Error("Erreur lors de la récupération de la série temporelle", "short_code", shortCode, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		total := 0
+		for _, bucket := range buckets {
+			total += bucket.Clicks
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"short_code":   link.ShortCode,
+			"interval":     interval,
+			"timezone":     loc.String(),
+			"from":         from.In(loc),
+			"to":           to.In(loc),
+			"total_clicks": total,
+			"buckets":      buckets,
+		})
+	}
+}
+
+// GetLinkHealthHandler gère la récupération de l'historique de surveillance d'un lien.
+// Paramètres de requête : window (durée Go, ex: 24h, 168h ; défaut 24h) pour le calcul
+// de la disponibilité et limit (défaut 20, max 100) pour le nombre de vérifications retournées.
+func GetLinkHealthHandler(linkService *services.LinkService, healthService *services.HealthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shortCode := c.Param("shortCode")
+
+		var window time.Duration
+		if raw := c.Query("window"); raw != "" {
+			d, err := time.ParseDuration(raw)
+			if err != nil || d <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "window must be a positive duration (e.g. 24h)"})
+				return
+			}
+			window = d
+		}
+		var limit int
+		if raw := c.Query("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+				return
+			}
+			limit = n
+		}
+
+		link, err := linkService.GetLinkByShortCode(shortCode)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Short URL not found"})
+				return
+			}
+			requestLogger(c).Error("Erreur lors de la récupération du lien", "short_code", shortCode, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		health, err := healthService.GetLinkHealth(link.ID, window, limit)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidHealthParams) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			requestLogger(c).Error("Erreur lors de la récupération de l'état de santé du lien", "short_code", shortCode, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		checks := make([]gin.H, 0, len(health.Recent))
+		for _, check := range health.Recent {
+			checks = append(checks, gin.H{
+				"checked_at":     check.CheckedAt,
+				"up":             check.Up,
+				"method":         check.Method,
+				"status_code":    check.StatusCode,
+				"latency_ms":     check.LatencyMs,
+				"error_class":    check.ErrorClass,
+				"error":          check.Error,
+				"redirect_chain": check.RedirectChain,
+			})
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"short_code":           link.ShortCode,
+			"long_url":             link.LongURL,
+			"state":                health.State,
+			"consecutive_failures": health.ConsecutiveFailures,
+			"last_checked_at":      health.LastCheckedAt,
+			"window":               health.Window.String(),
+			"checks":               health.Checks,
+			"uptime_percent":       health.UptimePercent,
+			"history":              checks,
+		})
+	}
+}
+
+// GetLinkAuditHandler retourne le journal d'audit d'un lien (mesures automatiques du moniteur).
+// Paramètre de requête : limit (défaut services.DefaultAuditLimit).
+func GetLinkAuditHandler(linkService *services.LinkService, auditService *services.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shortCode := c.Param("shortCode")
+
+		var limit int
+		if raw := c.Query("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+				return
+			}
+			limit = n
+		}
+
+		link, err := linkService.GetLinkByShortCode(shortCode)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Short URL not found"})
+				return
+			}
+			requestLogger(c).Error("Erreur lors de la récupération du lien", "short_code", shortCode, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		events, err := auditService.GetLinkAudit(link.ID, limit)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidAuditParams) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			requestLogger(c).Error("Erreur lors de la récupération du journal d'audit du lien", "short_code", shortCode, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		entries := make([]gin.H, 0, len(events))
+		for _, event := range events {
+			entries = append(entries, gin.H{
+				"created_at": event.CreatedAt,
+				"actor":      event.Actor,
+				"action":     event.Action,
+				"detail":     event.Detail,
+			})
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"short_code":    link.ShortCode,
+			"health_action": link.HealthAction,
+			"down_since":    link.DownSince,
+			"events":        entries,
 		})
 	}
 }
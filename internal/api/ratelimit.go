@@ -0,0 +1,69 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/metrics"
+	"github.com/axellelanca/urlshortener/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimiters regroupe les budgets de requêtes appliqués par SetupRoutes.
+// Un limiteur nil désactive la limitation correspondante.
+type RateLimiters struct {
+	Create   *ratelimit.Limiter // Création de liens (POST /api/v1/links)
+	Redirect *ratelimit.Limiter // Redirections (GET /:shortCode)
+}
+
+// RateLimitMiddleware applique un budget de requêtes par client. Le client est identifié
+// par sa clé d'API quand la requête est authentifiée, par son adresse IP sinon.
+// Les en-têtes RateLimit-* sont ajoutés à chaque réponse ; au-delà du budget la requête
+// est refusée (429) avec un en-tête Retry-After.
+func RateLimitMiddleware(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	if limiter == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	limit := limiter.Limit()
+	policy := fmt.Sprintf("%d;w=%d", limit.Burst, ceilSeconds(limit.Window()))
+
+	return func(c *gin.Context) {
+		result, err := limiter.Allow(rateLimitKey(c))
+		if err != nil {
+			// En cas d'indisponibilité du store, on laisse passer plutôt que de bloquer le service.
+			requestLogger(c).Error("Erreur du store de limitation de débit", "limiter", limiter.Name(), "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", policy)
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			metrics.RateLimited.WithLabelValues(limiter.Name()).Inc()
+			c.Header("Retry-After", strconv.Itoa(max(ceilSeconds(result.RetryAfter), 1)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitKey identifie le client d'une requête pour la limitation de débit.
+func rateLimitKey(c *gin.Context) string {
+	if key := authenticatedKey(c); key != nil {
+		return "key:" + strconv.FormatUint(uint64(key.ID), 10)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// ceilSeconds arrondit une durée à la seconde supérieure.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
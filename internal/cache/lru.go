@@ -0,0 +1,158 @@
+// Package cache fournit un cache LRU en mémoire, borné en taille et à durée de vie par entrée.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Stats résume l'activité d'un cache depuis sa création.
+type Stats struct {
+	Hits      uint64 // Lectures servies par le cache
+	Misses    uint64 // Lectures absentes ou expirées
+	Evictions uint64 // Entrées évincées pour respecter la taille maximale
+	Entries   int    // Nombre d'entrées actuellement en cache
+}
+
+// LRU est un cache clé-valeur borné en taille dont chaque entrée expire après sa durée de vie.
+// Quand il est plein, l'entrée la moins récemment lue ou écrite est évincée.
+// Il est sûr en concurrence.
+type LRU[K comparable, V any] struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List          // Entrées, de la plus récemment utilisée à la plus ancienne
+	items      map[K]*list.Element // Clé -> élément de order
+	stats      Stats
+	generation uint64 // Incrémentée à chaque Remove ou Purge (voir SetIfGeneration)
+}
+
+// entry est une valeur en cache avec sa date d'expiration.
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// New crée un cache LRU contenant au plus maxEntries entrées (au moins une).
+func New[K comparable, V any](maxEntries int) *LRU[K, V] {
+	return &LRU[K, V]{
+		maxEntries: max(maxEntries, 1),
+		order:      list.New(),
+		items:      make(map[K]*list.Element),
+	}
+}
+
+// Get retourne la valeur associée à key si elle est présente et non expirée.
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[K, V])
+		if time.Now().Before(e.expiresAt) {
+			c.order.MoveToFront(el)
+			c.stats.Hits++
+			return e.value, true
+		}
+		c.removeElement(el)
+	}
+	c.stats.Misses++
+	var zero V
+	return zero, false
+}
+
+// Set associe value à key pour la durée ttl. Elle indique si une autre entrée
+// a été évincée pour faire de la place.
+func (c *LRU[K, V]) Set(key K, value V, ttl time.Duration) (evicted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.setLocked(key, value, ttl)
+}
+
+// SetIfGeneration associe value à key comme Set, mais seulement si aucun Remove ni Purge
+// n'a eu lieu depuis que Generation a retourné generation : une valeur lue avant une
+// invalidation n'est pas remise en cache. La vérification et l'écriture sont atomiques.
+func (c *LRU[K, V]) SetIfGeneration(key K, value V, ttl time.Duration, generation uint64) (stored, evicted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation != generation {
+		return false, false
+	}
+	return true, c.setLocked(key, value, ttl)
+}
+
+// Generation retourne le compteur d'invalidations, à passer ensuite à SetIfGeneration.
+func (c *LRU[K, V]) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation
+}
+
+// setLocked associe value à key et évince l'entrée la plus ancienne si besoin.
+// L'appelant doit détenir c.mu.
+func (c *LRU[K, V]) setLocked(key K, value V, ttl time.Duration) (evicted bool) {
+	expiresAt := time.Now().Add(ttl)
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[K, V])
+		e.value, e.expiresAt = value, expiresAt
+		c.order.MoveToFront(el)
+		return false
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+	if c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+		c.stats.Evictions++
+		return true
+	}
+	return false
+}
+
+// Remove retire key du cache.
+func (c *LRU[K, V]) Remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+// Purge vide le cache. Les statistiques sont conservées.
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.order.Init()
+	clear(c.items)
+}
+
+// Len retourne le nombre d'entrées en cache, expirées comprises tant qu'elles n'ont pas été relues.
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// Stats retourne l'activité du cache.
+func (c *LRU[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	return stats
+}
+
+// removeElement retire une entrée. L'appelant doit détenir c.mu.
+func (c *LRU[K, V]) removeElement(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*entry[K, V]).key)
+}
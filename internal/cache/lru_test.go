@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[string, int](2)
+	c.Set("a", 1, time.Minute)
+	c.Set("b", 2, time.Minute)
+	if _, ok := c.Get("a"); !ok { // "a" devient la plus récemment utilisée
+		t.Fatalf("a missing")
+	}
+	if evicted := c.Set("c", 3, time.Minute); !evicted {
+		t.Fatalf("Set(c) did not report an eviction")
+	}
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("b still cached, want it evicted as least recently used")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if got, ok := c.Get(key); !ok || got != want {
+			t.Fatalf("Get(%s) = %d, %v, want %d", key, got, ok, want)
+		}
+	}
+	if stats := c.Stats(); stats.Evictions != 1 || stats.Entries != 2 {
+		t.Fatalf("stats = %+v, want 1 eviction and 2 entries", stats)
+	}
+}
+
+func TestEntriesExpire(t *testing.T) {
+	c := New[string, int](10)
+	c.Set("short", 1, 20*time.Millisecond)
+	c.Set("long", 2, time.Minute)
+
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := c.Get("short"); ok {
+		t.Fatalf("expired entry still served")
+	}
+	if _, ok := c.Get("long"); !ok {
+		t.Fatalf("unexpired entry missing")
+	}
+	if stats := c.Stats(); stats.Hits != 1 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Fatalf("stats = %+v, want 1 hit, 1 miss and 1 entry", stats)
+	}
+}
+
+func TestNegativeEntries(t *testing.T) {
+	// Une valeur nil représente une clé connue comme absente : elle est servie comme un hit.
+	c := New[string, *int](10)
+	c.Set("unknown", nil, time.Minute)
+
+	got, ok := c.Get("unknown")
+	if !ok || got != nil {
+		t.Fatalf("Get(unknown) = %v, %v, want cached nil", got, ok)
+	}
+	if _, ok := c.Get("other"); ok {
+		t.Fatalf("Get(other) hit, want miss")
+	}
+}
+
+func TestInvalidationBlocksStaleSet(t *testing.T) {
+	c := New[string, int](10)
+
+	generation := c.Generation()
+	c.Remove("a") // Invalidation concurrente d'une lecture commencée avant
+	if stored, _ := c.SetIfGeneration("a", 1, time.Minute, generation); stored {
+		t.Fatalf("value read before Remove was stored")
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("stale value cached after Remove")
+	}
+
+	generation = c.Generation()
+	if stored, _ := c.SetIfGeneration("a", 2, time.Minute, generation); !stored {
+		t.Fatalf("value read after Remove was not stored")
+	}
+	c.Purge()
+	if _, ok := c.Get("a"); ok || c.Len() != 0 {
+		t.Fatalf("entries left after Purge")
+	}
+	if stored, _ := c.SetIfGeneration("a", 3, time.Minute, generation); stored {
+		t.Fatalf("value read before Purge was stored")
+	}
+}
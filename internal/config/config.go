@@ -1,31 +1,126 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log" // Pour logger les informations ou erreurs de chargement de config
+
 	"github.com/spf13/viper" // La bibliothèque pour la gestion de configuration
 )
 
+// ErrInvalidConfig est retournée quand une valeur de configuration est hors de son domaine.
+var ErrInvalidConfig = errors.New("invalid configuration")
+
 // TODO Créer Config qui est la structure principale qui mappe l'intégralité de la configuration de l'application.
 // Les tags `mapstructure` sont utilisés par Viper pour mapper les clés du fichier de config
 // (ou des variables d'environnement) aux champs de la structure Go.
 type Config struct {
 	Server struct {
-		Port     int    `mapstructure:"port"`      // Port du serveur HTTP
-		BaseURL  string `mapstructure:"base_url"` // URL de base du serveur
+		Port    int    `mapstructure:"port"`     // Port du serveur HTTP
+		BaseURL string `mapstructure:"base_url"` // URL de base du serveur
+
+		ShutdownTimeoutSeconds int `mapstructure:"shutdown_timeout_seconds"` // Durée maximale de l'arrêt propre
 	} `mapstructure:"server"` // Sous-structure pour la configuration du serveur
 
 	Database struct {
-		Name     string `mapstructure:"name"`     // Nom de la base de donnéesAdd commentMore actions
+		Driver string `mapstructure:"driver"` // Moteur : sqlite, postgres, mysql ou memory
+		Name   string `mapstructure:"name"`   // Nom du fichier de la base de données (sqlite)
+		DSN    string `mapstructure:"dsn"`    // Chaîne de connexion (postgres, mysql)
+
+		MaxOpenConns           int `mapstructure:"max_open_conns"`             // Connexions ouvertes au maximum, 0 = illimité
+		MaxIdleConns           int `mapstructure:"max_idle_conns"`             // Connexions inactives conservées dans le pool
+		ConnMaxLifetimeMinutes int `mapstructure:"conn_max_lifetime_minutes"`  // Durée de vie maximale d'une connexion, 0 = illimitée
+		ConnMaxIdleTimeMinutes int `mapstructure:"conn_max_idle_time_minutes"` // Durée d'inactivité avant fermeture, 0 = illimitée
 	} `mapstructure:"database"` // Sous-structure pour la configuration de la base de données
 
 	Analytics struct {
-		BufferSize int `mapstructure:"buffer_size"` // Taille du tampon pour les données
-		WorkerCount int `mapstructure:"worker_count"` // Nombre de workers pour traiter les données
+		BufferSize  int    `mapstructure:"buffer_size"`  // Taille du tampon pour les données
+		WorkerCount int    `mapstructure:"worker_count"` // Nombre de workers pour traiter les données
+		VisitorSalt string `mapstructure:"visitor_salt"` // Sel des empreintes de visiteurs uniques
+
+		BatchSize       int `mapstructure:"batch_size"`        // Nombre de clics écrits par transaction
+		FlushIntervalMs int `mapstructure:"flush_interval_ms"` // Délai maximal avant l'écriture d'un lot incomplet
+		MaxRetries      int `mapstructure:"max_retries"`       // Tentatives supplémentaires si la base est verrouillée
+		RetryBackoffMs  int `mapstructure:"retry_backoff_ms"`  // Délai initial entre deux tentatives
+
+		Spill struct {
+			Enabled               bool   `mapstructure:"enabled"`                 // Active le débordement sur disque
+			Dir                   string `mapstructure:"dir"`                     // Répertoire des segments
+			SegmentMaxBytes       int64  `mapstructure:"segment_max_bytes"`       // Taille déclenchant un nouveau segment
+			ReplayIntervalSeconds int    `mapstructure:"replay_interval_seconds"` // Intervalle de vérification de la pression
+		} `mapstructure:"spill"` // Journal des clics qui ne tiennent pas dans le channel
 	} `mapstructure:"analytics"` // Sous-structure pour la configuration des analytics
 
 	Monitor struct {
-		IntervalMinutes int `mapstructure:"interval_minutes"` // Intervalle de surveillance en minutes
+		IntervalMinutes          int    `mapstructure:"interval_minutes"`             // Intervalle de surveillance en minutes
+		RetentionDays            int    `mapstructure:"retention_days"`               // Conservation de l'historique des vérifications (0 = illimitée)
+		Concurrency              int    `mapstructure:"concurrency"`                  // Nombre maximal de vérifications simultanées
+		PerHostRequestsPerMinute int    `mapstructure:"per_host_requests_per_minute"` // Vérifications par minute vers un même hôte (0 = illimité)
+		PerHostBurst             int    `mapstructure:"per_host_burst"`               // Rafale de vérifications autorisée vers un même hôte
+		JitterPercent            int    `mapstructure:"jitter_percent"`               // Part de l'intervalle sur laquelle étaler les vérifications
+		FailureThreshold         int    `mapstructure:"failure_threshold"`            // Échecs consécutifs avant de déclarer un lien inaccessible
+		UserAgent                string `mapstructure:"user_agent"`                   // User-Agent des requêtes de vérification
+		DeadLink                 struct {
+			Action      string `mapstructure:"action"`       // Mesure pour les destinations durablement inaccessibles : none, disable ou fallback
+			AfterHours  int    `mapstructure:"after_hours"`  // Durée d'inaccessibilité avant d'appliquer la mesure
+			FallbackURL string `mapstructure:"fallback_url"` // URL de repli globale (action fallback)
+		} `mapstructure:"dead_link"` // Politique pour les liens durablement inaccessibles
 	} `mapstructure:"monitor"` // Sous-structure pour la configuration du moniteur
+
+	Links struct {
+		FallbackURL          string `mapstructure:"fallback_url"`           // URL proposée quand un lien est expiré ou épuisé
+		SweepIntervalMinutes int    `mapstructure:"sweep_interval_minutes"` // Intervalle de marquage des liens expirés
+
+		DefaultRedirectType            int `mapstructure:"default_redirect_type"`              // Code de redirection des liens créés sans type explicite
+		PermanentRedirectMaxAgeSeconds int `mapstructure:"permanent_redirect_max_age_seconds"` // Mise en cache des redirections permanentes (0 = aucune)
+
+		Cache struct {
+			Enabled            bool `mapstructure:"enabled"`              // Active le cache de résolution des codes courts
+			MaxEntries         int  `mapstructure:"max_entries"`          // Nombre maximal de codes en cache
+			TTLSeconds         int  `mapstructure:"ttl_seconds"`          // Durée de vie d'un lien en cache
+			NegativeTTLSeconds int  `mapstructure:"negative_ttl_seconds"` // Durée de vie d'un code inconnu en cache
+		} `mapstructure:"cache"` // Cache en mémoire devant la résolution des redirections
+	} `mapstructure:"links"` // Sous-structure pour la durée de vie des liens
+
+	Notifications struct {
+		Webhooks []struct {
+			URL    string `mapstructure:"url"`    // Adresse du destinataire
+			Secret string `mapstructure:"secret"` // Secret de la signature HMAC-SHA256
+		} `mapstructure:"webhooks"` // Destinataires des événements
+		TimeoutSeconds int `mapstructure:"timeout_seconds"`  // Délai maximal d'une tentative de livraison
+		MaxRetries     int `mapstructure:"max_retries"`      // Nouvelles tentatives après un échec temporaire
+		RetryBackoffMs int `mapstructure:"retry_backoff_ms"` // Délai initial entre deux tentatives
+		QueueSize      int `mapstructure:"queue_size"`       // Événements en attente de livraison au-delà desquels ils sont abandonnés
+	} `mapstructure:"notifications"` // Sous-structure pour les notifications des changements d'état
+
+	URLSafety struct {
+		AllowedSchemes         []string `mapstructure:"allowed_schemes"`          // Schémas d'URL acceptés
+		BlockPrivateTargets    bool     `mapstructure:"block_private_targets"`    // Refuse les adresses privées et de bouclage
+		ResolveHosts           bool     `mapstructure:"resolve_hosts"`            // Résout les noms d'hôtes pour vérifier leurs adresses
+		BlocklistFile          string   `mapstructure:"blocklist_file"`           // Fichier des domaines interdits (vide = aucun)
+		BlocklistReloadSeconds int      `mapstructure:"blocklist_reload_seconds"` // Intervalle de détection des modifications
+	} `mapstructure:"url_safety"` // Sous-structure pour la validation des URLs de destination
+
+	Auth struct {
+		Enabled bool `mapstructure:"enabled"` // Exige une clé d'API sur /api/v1
+	} `mapstructure:"auth"` // Sous-structure pour l'authentification de l'API de gestion
+
+	RateLimit struct {
+		Enabled bool `mapstructure:"enabled"` // Active la limitation de débit par client
+		Create  struct {
+			RequestsPerMinute int `mapstructure:"requests_per_minute"` // Débit soutenu autorisé
+			Burst             int `mapstructure:"burst"`               // Rafale maximale
+		} `mapstructure:"create"` // Budget de création de liens (par clé d'API ou IP)
+		Redirect struct {
+			RequestsPerMinute int `mapstructure:"requests_per_minute"` // Débit soutenu autorisé
+			Burst             int `mapstructure:"burst"`               // Rafale maximale
+		} `mapstructure:"redirect"` // Budget de redirections (par IP)
+	} `mapstructure:"rate_limit"` // Sous-structure pour la limitation de débit
+
+	Log struct {
+		Level  string `mapstructure:"level"`  // Niveau minimal : debug, info, warn ou error
+		Format string `mapstructure:"format"` // Format de sortie : text ou json
+	} `mapstructure:"log"` // Sous-structure pour les logs structurés
 }
 
 // LoadConfig charge la configuration de l'application en utilisant Viper.
@@ -37,7 +132,7 @@ func LoadConfig() (*Config, error) {
 	viper.AddConfigPath("./configs") // Chemin relatif au répertoire d'exécution
 
 	// TODO Spécifie le nom du fichier de config (sans l'extension).
-	viper.SetConfigName("config")     // Nom du fichier de configuration sans l'extension
+	viper.SetConfigName("config") // Nom du fichier de configuration sans l'extension
 
 	// TODO Spécifie le type de fichier de config.
 	viper.SetConfigType("yaml")
@@ -48,13 +143,74 @@ func LoadConfig() (*Config, error) {
 	// server.port, server.base_url etc.
 	viper.SetDefault("server.port", 8080)
 	viper.SetDefault("server.base_url", "http://localhost:8080")
-	
+	viper.SetDefault("server.shutdown_timeout_seconds", 15)
+
+	viper.SetDefault("database.driver", "sqlite")
 	viper.SetDefault("database.name", "url_shortener.db")
+	viper.SetDefault("database.dsn", "")
+	viper.SetDefault("database.max_open_conns", 20)
+	viper.SetDefault("database.max_idle_conns", 5)
+	viper.SetDefault("database.conn_max_lifetime_minutes", 30)
+	viper.SetDefault("database.conn_max_idle_time_minutes", 5)
+	// La chaîne de connexion contient souvent un mot de passe : elle peut être fournie
+	// par l'environnement plutôt que dans le fichier de configuration.
+	viper.BindEnv("database.dsn", "URLSHORTENER_DATABASE_DSN")
 
 	viper.SetDefault("analytics.buffer_size", 1000)
 	viper.SetDefault("analytics.worker_count", 5)
+	viper.SetDefault("analytics.visitor_salt", "")
+	viper.SetDefault("analytics.batch_size", 100)
+	viper.SetDefault("analytics.flush_interval_ms", 1000)
+	viper.SetDefault("analytics.max_retries", 5)
+	viper.SetDefault("analytics.retry_backoff_ms", 50)
+	viper.SetDefault("analytics.spill.enabled", false)
+	viper.SetDefault("analytics.spill.dir", "click_spill")
+	viper.SetDefault("analytics.spill.segment_max_bytes", 1<<20)
+	viper.SetDefault("analytics.spill.replay_interval_seconds", 10)
 
 	viper.SetDefault("monitor.interval_minutes", 5)
+	viper.SetDefault("monitor.retention_days", 30)
+	viper.SetDefault("monitor.concurrency", 10)
+	viper.SetDefault("monitor.per_host_requests_per_minute", 30)
+	viper.SetDefault("monitor.per_host_burst", 2)
+	viper.SetDefault("monitor.jitter_percent", 50)
+	viper.SetDefault("monitor.failure_threshold", 3)
+	viper.SetDefault("monitor.dead_link.action", "none")
+	viper.SetDefault("monitor.dead_link.after_hours", 168)
+	viper.SetDefault("monitor.dead_link.fallback_url", "")
+	viper.SetDefault("monitor.user_agent", "urlshortener-monitor/1.0 (+link health check)")
+
+	viper.SetDefault("links.fallback_url", "")
+	viper.SetDefault("links.sweep_interval_minutes", 1)
+	viper.SetDefault("links.default_redirect_type", 302)
+	viper.SetDefault("links.permanent_redirect_max_age_seconds", 86400)
+	viper.SetDefault("links.cache.enabled", true)
+	viper.SetDefault("links.cache.max_entries", 10000)
+	viper.SetDefault("links.cache.ttl_seconds", 60)
+	viper.SetDefault("links.cache.negative_ttl_seconds", 10)
+
+	viper.SetDefault("notifications.webhooks", []map[string]string{})
+	viper.SetDefault("notifications.timeout_seconds", 5)
+	viper.SetDefault("notifications.max_retries", 3)
+	viper.SetDefault("notifications.retry_backoff_ms", 500)
+	viper.SetDefault("notifications.queue_size", 100)
+
+	viper.SetDefault("url_safety.allowed_schemes", []string{"http", "https"})
+	viper.SetDefault("url_safety.block_private_targets", true)
+	viper.SetDefault("url_safety.resolve_hosts", true)
+	viper.SetDefault("url_safety.blocklist_file", "")
+	viper.SetDefault("url_safety.blocklist_reload_seconds", 30)
+
+	viper.SetDefault("auth.enabled", true)
+
+	viper.SetDefault("rate_limit.enabled", true)
+	viper.SetDefault("rate_limit.create.requests_per_minute", 30)
+	viper.SetDefault("rate_limit.create.burst", 10)
+	viper.SetDefault("rate_limit.redirect.requests_per_minute", 600)
+	viper.SetDefault("rate_limit.redirect.burst", 100)
+
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.format", "text")
 
 	// TODO : Lire le fichier de configuration.
 	if err := viper.ReadInConfig(); err != nil {
@@ -69,9 +225,36 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
-	// Log  pour vérifier la config chargée
-	log.Printf("Configuration loaded: Server Port=%d, DB Name=%s, Analytics Buffer=%d, Monitor Interval=%dmin",
-		cfg.Server.Port, cfg.Database.Name, cfg.Analytics.BufferSize, cfg.Monitor.IntervalMinutes)
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil // Retourne la configuration chargée
 }
+
+// validate refuse les valeurs qui feraient échouer un composant après le démarrage,
+// comme un intervalle nul que time.NewTicker rejette par une panique.
+func (c *Config) validate() error {
+	type setting struct {
+		key   string
+		value int64
+	}
+	positives := []setting{
+		{"server.shutdown_timeout_seconds", int64(c.Server.ShutdownTimeoutSeconds)},
+		{"analytics.worker_count", int64(c.Analytics.WorkerCount)},
+		{"monitor.interval_minutes", int64(c.Monitor.IntervalMinutes)},
+		{"links.sweep_interval_minutes", int64(c.Links.SweepIntervalMinutes)},
+	}
+	if c.Analytics.Spill.Enabled {
+		positives = append(positives,
+			setting{"analytics.spill.segment_max_bytes", c.Analytics.Spill.SegmentMaxBytes},
+			setting{"analytics.spill.replay_interval_seconds", int64(c.Analytics.Spill.ReplayIntervalSeconds)},
+		)
+	}
+	for _, s := range positives {
+		if s.value <= 0 {
+			return fmt.Errorf("%w: %s must be positive, got %d", ErrInvalidConfig, s.key, s.value)
+		}
+	}
+	return nil
+}
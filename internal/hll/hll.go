@@ -0,0 +1,88 @@
+package hll
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+)
+
+// precision est le nombre de bits du hash utilisés pour choisir un registre.
+// Avec 2^12 registres d'un octet, un sketch occupe 4 Ko pour une erreur type d'environ 1,6 %.
+const (
+	precision = 12
+	registers = 1 << precision
+)
+
+// ErrInvalidSketch est retournée quand des données sérialisées ne correspondent pas à un sketch.
+var ErrInvalidSketch = errors.New("invalid hyperloglog sketch")
+
+// Sketch est un estimateur HyperLogLog du nombre d'éléments distincts.
+// Il ne conserve aucun élément, seulement le rang maximal observé par registre,
+// ce qui permet de fusionner des sketches sans perte (union).
+type Sketch struct {
+	registers []uint8
+}
+
+// New crée un sketch vide.
+func New() *Sketch {
+	return &Sketch{registers: make([]uint8, registers)}
+}
+
+// FromBytes reconstruit un sketch à partir de sa représentation sérialisée (voir Bytes).
+func FromBytes(data []byte) (*Sketch, error) {
+	if len(data) != registers {
+		return nil, ErrInvalidSketch
+	}
+	s := New()
+	copy(s.registers, data)
+	return s, nil
+}
+
+// Bytes retourne la représentation sérialisée du sketch, un octet par registre.
+func (s *Sketch) Bytes() []byte {
+	out := make([]byte, len(s.registers))
+	copy(out, s.registers)
+	return out
+}
+
+// Add ajoute un élément, représenté par un hash 64 bits uniformément distribué.
+func (s *Sketch) Add(hash uint64) {
+	index := hash >> (64 - precision)
+	// Le bit sentinelle borne le rang quand les bits restants sont tous à zéro.
+	remaining := hash<<precision | 1<<(precision-1)
+	rank := uint8(bits.LeadingZeros64(remaining) + 1)
+	if rank > s.registers[index] {
+		s.registers[index] = rank
+	}
+}
+
+// Merge ajoute au sketch tous les éléments de other.
+func (s *Sketch) Merge(other *Sketch) {
+	for i, r := range other.registers {
+		if r > s.registers[i] {
+			s.registers[i] = r
+		}
+	}
+}
+
+// Count retourne l'estimation du nombre d'éléments distincts ajoutés.
+func (s *Sketch) Count() uint64 {
+	m := float64(registers)
+	alpha := 0.7213 / (1 + 1.079/m)
+
+	sum := 0.0
+	zeros := 0
+	for _, r := range s.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	estimate := alpha * m * m / sum
+	// Correction pour les petites cardinalités : comptage linéaire sur les registres vides.
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// slowQueryThreshold est la durée au-delà de laquelle une requête SQL est signalée comme lente.
+const slowQueryThreshold = 200 * time.Millisecond
+
+// gormLogger redirige les logs de GORM vers slog, à la place de son logger texte coloré.
+type gormLogger struct {
+	logger *slog.Logger
+	level  gormlogger.LogLevel
+}
+
+// NewGormLogger retourne un logger GORM qui écrit dans le logger par défaut.
+// Seules les erreurs SQL et les requêtes lentes sont journalisées ; une ligne
+// introuvable (gorm.ErrRecordNotFound) n'est pas considérée comme une erreur.
+// Les requêtes sont journalisées au niveau debug.
+func NewGormLogger() gormlogger.Interface {
+	return &gormLogger{logger: Component("database"), level: gormlogger.Info}
+}
+
+// LogMode implémente gormlogger.Interface.
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+// Info implémente gormlogger.Interface.
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.logger.InfoContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Warn implémente gormlogger.Interface.
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.logger.WarnContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Error implémente gormlogger.Interface.
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.logger.ErrorContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace implémente gormlogger.Interface et journalise une requête exécutée.
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		sql, rows := fc()
+		l.logger.ErrorContext(ctx, "Erreur SQL", "sql", sql, "rows", rows, "duration", elapsed.String(), "error", err)
+	case elapsed > slowQueryThreshold && l.level >= gormlogger.Warn:
+		sql, rows := fc()
+		l.logger.WarnContext(ctx, "Requête SQL lente", "sql", sql, "rows", rows, "duration", elapsed.String())
+	case l.logger.Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		l.logger.DebugContext(ctx, "Requête SQL", "sql", sql, "rows", rows, "duration", elapsed.String())
+	}
+}
@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Formats de sortie des logs acceptés dans la configuration.
+const (
+	FormatText = "text" // Paires clé=valeur, lisibles dans un terminal
+	FormatJSON = "json" // Un objet JSON par ligne, pour les pipelines de logs
+)
+
+// Erreurs de configuration du logger.
+var (
+	ErrInvalidLevel  = errors.New("invalid log level (expected debug, info, warn or error)")
+	ErrInvalidFormat = errors.New("invalid log format (expected text or json)")
+)
+
+// New construit un logger slog écrivant dans w au niveau et au format demandés.
+// Un niveau ou un format vide prend la valeur par défaut (info, text).
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidLevel, level)
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case "", FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidFormat, format)
+	}
+}
+
+// Setup installe sur la sortie d'erreur standard le logger par défaut de l'application.
+// Les appels restants au paquet log passent eux aussi par ce logger, au niveau info.
+func Setup(level, format string) error {
+	logger, err := New(os.Stderr, level, format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
+}
+
+// Component retourne le logger par défaut annoté du nom du composant (monitor, worker...).
+func Component(name string) *slog.Logger {
+	return slog.Default().With("component", name)
+}
@@ -0,0 +1,181 @@
+package metrics
+
+import (
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// namespace préfixe toutes les métriques exposées par le service.
+const namespace = "urlshortener"
+
+// Métriques HTTP de l'API et des redirections.
+var (
+	// RedirectDuration mesure la durée de traitement des redirections, par code de statut HTTP.
+	RedirectDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "redirect_duration_seconds",
+		Help:      "Durée de traitement des requêtes de redirection, par code de statut HTTP.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"status"})
+
+	// RateLimited compte les requêtes refusées (429) par la limitation de débit, par budget.
+	RateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_total",
+		Help:      "Nombre de requêtes refusées par la limitation de débit, par budget.",
+	}, []string{"limiter"})
+
+	// LinksCreated compte les liens créés via l'API.
+	LinksCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "links_created_total",
+		Help:      "Nombre de liens courts créés via l'API.",
+	})
+)
+
+// Métriques du pipeline asynchrone des clics.
+var (
+	// ClicksRecorded compte les clics persistés en base par les workers.
+	ClicksRecorded = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "clicks_recorded_total",
+		Help:      "Nombre de clics enregistrés en base par les workers.",
+	})
+
+	// ClicksSpilled compte les clics écrits dans le journal de débordement sur disque.
+	ClicksSpilled = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "clicks_spilled_total",
+		Help:      "Nombre de clics mis de côté sur disque car le channel était plein.",
+	})
+
+	// ClicksDropped compte les clics définitivement perdus, par raison
+	// (channel_full : channel plein sans débordement sur disque, spill_failed : écriture sur disque
+	// en échec, persist_failed : lot abandonné par les workers).
+	ClicksDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "clicks_dropped_total",
+		Help:      "Nombre de clics perdus, par raison.",
+	}, []string{"reason"})
+
+	// WorkerFailures compte les lots de clics que les workers n'ont pas pu persister.
+	WorkerFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "click_worker_failures_total",
+		Help:      "Nombre de lots de clics en échec d'écriture après toutes les tentatives.",
+	})
+
+	// WorkerRetries compte les nouvelles tentatives d'écriture dues à une base verrouillée.
+	WorkerRetries = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "click_worker_retries_total",
+		Help:      "Nombre de nouvelles tentatives d'écriture de lots de clics (base verrouillée).",
+	})
+)
+
+// Métriques du moniteur d'URLs.
+var (
+	// MonitorCheckDuration mesure la durée de vérification d'une URL longue.
+	MonitorCheckDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "monitor_check_duration_seconds",
+		Help:      "Durée de vérification de l'accessibilité d'une URL longue.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 10),
+	})
+
+	// MonitorLinks indique le nombre de liens surveillés par état (up ou down) lors du dernier passage.
+	MonitorLinks = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "monitor_links",
+		Help:      "Nombre de liens surveillés accessibles (up) ou inaccessibles (down) au dernier passage.",
+	}, []string{"state"})
+
+	// MonitorPassDuration mesure la durée d'un passage complet du moniteur.
+	MonitorPassDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "monitor_pass_duration_seconds",
+		Help:      "Durée d'un passage complet de vérification des URLs.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+	})
+
+	// MonitorCheckErrors compte les vérifications en échec par classe d'erreur (dns, tls, timeout, http...).
+	MonitorCheckErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "monitor_check_errors_total",
+		Help:      "Nombre de vérifications d'URL en échec, par classe d'erreur.",
+	}, []string{"class"})
+
+	// MonitorDeadLinkActions compte les mesures appliquées ou levées par le moniteur sur les liens
+	// durablement inaccessibles, par action (auto_disabled, fallback_enabled, reinstated).
+	MonitorDeadLinkActions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "monitor_dead_link_actions_total",
+		Help:      "Nombre de mesures appliquées ou levées sur les liens à la destination durablement inaccessible.",
+	}, []string{"action"})
+
+	// MonitorPassesSkipped compte les passages ignorés parce que le précédent n'était pas terminé.
+	MonitorPassesSkipped = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "monitor_passes_skipped_total",
+		Help:      "Nombre de passages du moniteur ignorés car le passage précédent était toujours en cours.",
+	})
+
+	// MonitorHostThrottled compte les vérifications retardées par le budget par hôte.
+	MonitorHostThrottled = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "monitor_host_throttled_total",
+		Help:      "Nombre d'attentes imposées par le budget de vérifications par hôte de destination.",
+	})
+
+	// NotificationsDropped compte les notifications abandonnées parce que la file de livraison était pleine.
+	NotificationsDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_dropped_total",
+		Help:      "Nombre de notifications de changement d'état abandonnées, la file de livraison étant pleine.",
+	})
+)
+
+// Métriques du cache de résolution des codes courts.
+var (
+	// LinkCacheLookups compte les résolutions de codes courts par résultat
+	// (hit : lien en cache, negative_hit : code inconnu en cache, miss : lecture en base).
+	LinkCacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "link_cache_lookups_total",
+		Help:      "Nombre de résolutions de codes courts via le cache, par résultat.",
+	}, []string{"result"})
+
+	// LinkCacheEvictions compte les entrées évincées du cache faute de place.
+	LinkCacheEvictions = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "link_cache_evictions_total",
+		Help:      "Nombre d'entrées évincées du cache des liens car il était plein.",
+	})
+)
+
+// RegisterClickChannel expose la profondeur et la capacité du channel des événements de clic.
+// Elle doit être appelée une seule fois, avec le channel utilisé par les workers.
+func RegisterClickChannel(ch chan models.ClickEvent) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "click_channel_depth",
+		Help:      "Nombre d'événements de clic en attente dans le channel.",
+	}, func() float64 { return float64(len(ch)) })
+
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "click_channel_capacity",
+		Help:      "Capacité du channel des événements de clic.",
+	}, func() float64 { return float64(cap(ch)) })
+}
+
+// RegisterLinkCache expose le nombre d'entrées du cache des liens.
+// Elle doit être appelée une seule fois, avec la fonction retournant ce nombre.
+func RegisterLinkCache(entries func() int) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "link_cache_entries",
+		Help:      "Nombre d'entrées (liens et codes inconnus) dans le cache des liens.",
+	}, func() float64 { return float64(entries()) })
+}
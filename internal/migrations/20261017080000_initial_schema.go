@@ -0,0 +1,115 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Schéma initial, tel que le créait AutoMigrate avant l'introduction des migrations versionnées.
+// Les structures sont figées ici : les modèles peuvent évoluer sans modifier cette étape.
+// AutoMigrate la rend idempotente sur une base créée par une version antérieure.
+
+type initialLink struct {
+	ID           uint           `gorm:"primaryKey"`
+	ShortCode    string         `gorm:"uniqueIndex;size:32"`
+	LongURL      string         `gorm:"not null"`
+	CreatedAt    time.Time      `gorm:"autoCreateTime"`
+	ExpiresAt    *time.Time     `gorm:"index"`
+	MaxClicks    int            `gorm:"not null;default:0"`
+	Expired      bool           `gorm:"index;default:false"`
+	Disabled     bool           `gorm:"index;default:false"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+	FallbackURL  string         `gorm:"size:2048"`
+	DownSince    *time.Time
+	HealthAction string `gorm:"size:16;not null;default:''"`
+}
+
+func (initialLink) TableName() string { return "links" }
+
+type initialClick struct {
+	ID           uint        `gorm:"primaryKey"`
+	LinkID       uint        `gorm:"index"`
+	Link         initialLink `gorm:"foreignKey:LinkID"`
+	Timestamp    time.Time
+	UserAgent    string `gorm:"size:255"`
+	IPAddress    string `gorm:"size:50"`
+	Referrer     string `gorm:"size:512"`
+	ReferrerHost string `gorm:"size:255"`
+	Browser      string `gorm:"size:50"`
+	OS           string `gorm:"size:50"`
+	DeviceType   string `gorm:"size:20"`
+}
+
+func (initialClick) TableName() string { return "clicks" }
+
+type initialVisitorSketch struct {
+	LinkID    uint      `gorm:"primaryKey;autoIncrement:false"`
+	Registers []byte    `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (initialVisitorSketch) TableName() string { return "visitor_sketches" }
+
+type initialAPIKey struct {
+	ID         uint      `gorm:"primaryKey"`
+	Name       string    `gorm:"size:100;not null"`
+	Prefix     string    `gorm:"size:16;index;not null"`
+	KeyHash    string    `gorm:"size:64;uniqueIndex;not null"`
+	Scopes     string    `gorm:"size:255;not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time `gorm:"index"`
+}
+
+func (initialAPIKey) TableName() string { return "api_keys" }
+
+type initialRedirectHop struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+}
+
+type initialLinkCheck struct {
+	ID                  uint      `gorm:"primaryKey"`
+	LinkID              uint      `gorm:"index:idx_link_checks_link_time;not null"`
+	CheckedAt           time.Time `gorm:"index:idx_link_checks_link_time;index"`
+	Up                  bool
+	StatusCode          int
+	LatencyMs           int64
+	Error               string               `gorm:"size:512"`
+	Method              string               `gorm:"size:8"`
+	ErrorClass          string               `gorm:"size:16"`
+	RedirectChain       []initialRedirectHop `gorm:"serializer:json"`
+	State               string               `gorm:"size:8"`
+	ConsecutiveFailures int
+}
+
+func (initialLinkCheck) TableName() string { return "link_checks" }
+
+type initialLinkAuditEvent struct {
+	ID        uint      `gorm:"primaryKey"`
+	LinkID    uint      `gorm:"index;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	Actor     string    `gorm:"size:32;not null"`
+	Action    string    `gorm:"size:32;not null"`
+	Detail    string    `gorm:"size:512"`
+}
+
+func (initialLinkAuditEvent) TableName() string { return "link_audit_events" }
+
+func init() {
+	register(Migration{
+		Version: "20261017080000",
+		Name:    "initial_schema",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&initialLink{}, &initialClick{}, &initialVisitorSketch{},
+				&initialAPIKey{}, &initialLinkCheck{}, &initialLinkAuditEvent{})
+		},
+		Down: func(tx *gorm.DB) error {
+			// Les clics référencent les liens : ils sont supprimés en premier.
+			return tx.Migrator().DropTable(&initialLinkAuditEvent{}, &initialLinkCheck{}, &initialAPIKey{},
+				&initialVisitorSketch{}, &initialClick{}, &initialLink{})
+		},
+	})
+}
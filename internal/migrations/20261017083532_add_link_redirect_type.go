@@ -0,0 +1,25 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// Code de redirection HTTP propre à chaque lien. Les liens existants gardent
+// la redirection temporaire (302) qu'ils utilisaient jusqu'ici.
+
+type linkRedirectType struct {
+	RedirectType int `gorm:"not null;default:302"`
+}
+
+func (linkRedirectType) TableName() string { return "links" }
+
+func init() {
+	register(Migration{
+		Version: "20261017083532",
+		Name:    "add_link_redirect_type",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AddColumn(&linkRedirectType{}, "RedirectType")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&linkRedirectType{}, "RedirectType")
+		},
+	})
+}
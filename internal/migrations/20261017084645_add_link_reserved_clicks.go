@@ -0,0 +1,29 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// Compteur des redirections réservées sur le budget de clics, incrémenté de façon synchrone
+// à chaque redirection. Il est initialisé avec les clics déjà enregistrés des liens à budget.
+
+type linkReservedClicks struct {
+	ReservedClicks int `gorm:"not null;default:0"`
+}
+
+func (linkReservedClicks) TableName() string { return "links" }
+
+func init() {
+	register(Migration{
+		Version: "20261017084645",
+		Name:    "add_link_reserved_clicks",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&linkReservedClicks{}, "ReservedClicks"); err != nil {
+				return err
+			}
+			return tx.Exec("UPDATE links SET reserved_clicks = " +
+				"(SELECT COUNT(*) FROM clicks WHERE clicks.link_id = links.id) WHERE max_clicks > 0").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&linkReservedClicks{}, "ReservedClicks")
+		},
+	})
+}
@@ -0,0 +1,303 @@
+// Package migrations gère l'évolution versionnée du schéma de la base de données.
+// Chaque migration est une étape réversible (Up / Down) enregistrée dans la table
+// schema_migrations une fois appliquée.
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/logging"
+	"gorm.io/gorm"
+)
+
+// versionLayout est le format des versions : un horodatage UTC qui ordonne les migrations.
+const versionLayout = "20060102150405"
+
+var (
+	// ErrSchemaBehind est retournée quand des migrations connues n'ont pas été appliquées.
+	ErrSchemaBehind = errors.New("database schema is behind")
+	// ErrUnknownMigration est retournée quand la base contient une migration inconnue de ce binaire.
+	ErrUnknownMigration = errors.New("applied migration is unknown to this binary")
+	// ErrInvalidMigrationName est retournée quand le nom d'une nouvelle migration est invalide.
+	ErrInvalidMigrationName = errors.New("invalid migration name")
+)
+
+// migrationNamePattern limite les noms de migration à des identifiants utilisables dans un nom de fichier.
+var migrationNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// Migration est une étape versionnée et réversible du schéma.
+// Up et Down reçoivent une transaction : une étape échouée n'est pas enregistrée.
+type Migration struct {
+	Version string // Horodatage UTC (AAAAMMJJHHMMSS), détermine l'ordre d'application
+	Name    string // Description courte en snake_case
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration enregistre une migration appliquée à la base.
+type SchemaMigration struct {
+	Version   string    `gorm:"primaryKey;size:14"` // Version de la migration
+	Name      string    `gorm:"size:64;not null"`   // Nom de la migration au moment de son application
+	AppliedAt time.Time `gorm:"not null"`           // Date d'application (UTC)
+}
+
+// TableName fixe le nom de la table d'historique.
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status décrit l'état d'une migration, connue du binaire ou seulement présente en base.
+type Status struct {
+	Version   string
+	Name      string
+	AppliedAt *time.Time // nil tant que la migration n'est pas appliquée
+	Unknown   bool       // Appliquée en base mais absente de ce binaire
+}
+
+// registry contient les migrations déclarées par les fichiers du package.
+var registry []Migration
+
+// register ajoute une migration au registre ; appelée depuis l'init de chaque fichier de migration.
+func register(m Migration) {
+	if _, err := time.Parse(versionLayout, m.Version); err != nil {
+		panic(fmt.Sprintf("migrations: invalid version %q", m.Version))
+	}
+	for _, existing := range registry {
+		if existing.Version == m.Version {
+			panic(fmt.Sprintf("migrations: duplicate version %s", m.Version))
+		}
+	}
+	registry = append(registry, m)
+}
+
+// All retourne les migrations déclarées, triées par version croissante.
+func All() []Migration {
+	all := append([]Migration(nil), registry...)
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	return all
+}
+
+// Migrator applique et annule les migrations sur une base de données.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+// NewMigrator crée un Migrator pour les migrations déclarées dans ce package.
+func NewMigrator(db *gorm.DB) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: All(),
+		logger:     logging.Component("migrations"),
+	}
+}
+
+// ensureTable crée la table d'historique si elle n'existe pas encore.
+func (m *Migrator) ensureTable() error {
+	if err := m.db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// applied retourne les migrations enregistrées en base, indexées par version.
+func (m *Migrator) applied() (map[string]SchemaMigration, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+	var rows []SchemaMigration
+	if err := m.db.Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	applied := make(map[string]SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// Status retourne l'état de chaque migration, y compris celles appliquées en base
+// mais inconnues de ce binaire (par exemple après un retour à une version antérieure).
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, row := range applied {
+		appliedAt := row.AppliedAt
+		statuses = append(statuses, Status{Version: row.Version, Name: row.Name, AppliedAt: &appliedAt, Unknown: true})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Pending retourne les migrations non appliquées, dans l'ordre d'application.
+func (m *Migrator) Pending() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// CheckCurrent retourne ErrSchemaBehind si des migrations restent à appliquer.
+func (m *Migrator) CheckCurrent() error {
+	pending, err := m.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %d pending migration(s), starting with %s_%s",
+			ErrSchemaBehind, len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
+}
+
+// Up applique toutes les migrations en attente et retourne celles qui l'ont été.
+// Chaque migration est appliquée et enregistrée dans sa propre transaction ;
+// en cas d'échec, les migrations précédentes restent appliquées.
+func (m *Migrator) Up() ([]Migration, error) {
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range pending {
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now().UTC(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("failed to apply migration %s_%s: %w", migration.Version, migration.Name, err)
+		}
+		m.logger.Info("Migration appliquée", "version", migration.Version, "name", migration.Name)
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down annule les steps dernières migrations appliquées, de la plus récente à la plus ancienne,
+// et retourne celles qui l'ont été.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(versions)))
+	if steps < 0 {
+		steps = 0
+	}
+	if steps < len(versions) {
+		versions = versions[:steps]
+	}
+
+	known := make(map[string]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	var done []Migration
+	for _, version := range versions {
+		migration, ok := known[version]
+		if !ok {
+			return done, fmt.Errorf("%w: %s_%s", ErrUnknownMigration, version, applied[version].Name)
+		}
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{Version: version}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("failed to roll back migration %s_%s: %w", migration.Version, migration.Name, err)
+		}
+		m.logger.Info("Migration annulée", "version", migration.Version, "name", migration.Name)
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// migrationTemplate est le squelette d'un nouveau fichier de migration.
+const migrationTemplate = `package migrations
+
+import "gorm.io/gorm"
+
+func init() {
+	register(Migration{
+		Version: %q,
+		Name:    %q,
+		Up: func(tx *gorm.DB) error {
+			// TODO : appliquer la modification du schéma.
+			// Déclarer ici des structures figées plutôt que les modèles, qui évolueront.
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			// TODO : annuler exactement la modification appliquée par Up.
+			return nil
+		},
+	})
+}
+`
+
+// Create écrit le squelette d'une nouvelle migration dans dir et retourne le chemin du fichier.
+// La version est dérivée de now, en UTC.
+func Create(dir, name string, now time.Time) (string, error) {
+	if !migrationNamePattern.MatchString(name) {
+		return "", fmt.Errorf("%w %q: use lowercase letters, digits and underscores", ErrInvalidMigrationName, name)
+	}
+
+	version := now.UTC().Format(versionLayout)
+	source, err := format.Source([]byte(fmt.Sprintf(migrationTemplate, version, name)))
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, version+"_"+name+".go")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Write(source); err != nil {
+		file.Close()
+		return "", err
+	}
+	return path, file.Close()
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Portées (scopes) attribuables à une clé d'API.
+const (
+	ScopeLinksRead  = "links:read"  // Lister les liens
+	ScopeLinksWrite = "links:write" // Créer, modifier et supprimer des liens
+	ScopeStatsRead  = "stats:read"  // Consulter les statistiques des liens
+)
+
+// AllScopes liste toutes les portées connues, dans l'ordre d'affichage.
+var AllScopes = []string{ScopeLinksRead, ScopeLinksWrite, ScopeStatsRead}
+
+// APIKey représente une clé d'accès à l'API de gestion.
+// Seul le hash SHA-256 de la clé est conservé : la clé en clair n'est affichée qu'à sa création.
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey"`
+	Name       string     `gorm:"size:100;not null"`            // Libellé libre (ex: "site marketing")
+	Prefix     string     `gorm:"size:16;index;not null"`       // Début de la clé, affiché pour l'identifier
+	KeyHash    string     `gorm:"size:64;uniqueIndex;not null"` // SHA-256 hexadécimal de la clé complète
+	Scopes     string     `gorm:"size:255;not null"`            // Portées séparées par des espaces
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`               // Date de création
+	LastUsedAt *time.Time // Dernière utilisation (mise à jour au plus une fois par minute)
+	RevokedAt  *time.Time `gorm:"index"` // Date de révocation, nil tant que la clé est valide
+}
+
+// ScopeList retourne les portées de la clé sous forme de liste.
+func (k *APIKey) ScopeList() []string {
+	return strings.Fields(k.Scopes)
+}
+
+// HasScope indique si la clé dispose de la portée demandée.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRevoked indique si la clé a été révoquée.
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
@@ -11,6 +11,12 @@ type Click struct {
 	Timestamp time.Time // Horodatage précis du clic
 	UserAgent string    `gorm:"size:255"` // User-Agent de l'utilisateur qui a cliqué (informations sur le navigateur/OS)
 	IPAddress string    `gorm:"size:50"`  // Adresse IP de l'utilisateur
+
+	Referrer     string `gorm:"size:512"` // En-tête Referer brut (vide pour un accès direct)
+	ReferrerHost string `gorm:"size:255"` // Domaine du referrer, utilisé pour les répartitions
+	Browser      string `gorm:"size:50"`  // Navigateur déduit du User-Agent à l'écriture
+	OS           string `gorm:"size:50"`  // Système d'exploitation déduit du User-Agent
+	DeviceType   string `gorm:"size:20"`  // Classe d'appareil : desktop, mobile, tablet, bot ou unknown
 }
 
 // TODO créer la struct pour ClickEvent
@@ -23,4 +29,15 @@ type ClickEvent struct {
 	Timestamp time.Time
 	UserAgent string
 	IPAddress string
-}
\ No newline at end of file
+	Referrer  string
+	RequestID string // Identifiant de corrélation de la requête de redirection, repris dans les logs des workers
+}
+
+// VisitorSketch stocke, pour chaque lien, le sketch HyperLogLog des visiteurs uniques.
+// Chaque visiteur y est représenté par un hash salé de (jour, IP, User-Agent) :
+// aucune donnée brute n'est conservée dans cette table.
+type VisitorSketch struct {
+	LinkID    uint      `gorm:"primaryKey;autoIncrement:false"` // Lien concerné
+	Registers []byte    `gorm:"not null"`                       // Registres HyperLogLog sérialisés
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`                 // Dernière fusion
+}
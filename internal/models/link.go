@@ -1,18 +1,71 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // TODO : Créer la struct Link
 // Link représente un lien raccourci dans la base de données.
 // Les tags `gorm:"..."` définissent comment GORM doit mapper cette structure à une table SQL.
 // ID qui est une primaryKey
-// Shortcode : doit être unique, indexé pour des recherches rapide (voir doc), taille max 10 caractères
+// Shortcode : doit être unique, indexé pour des recherches rapide (voir doc), taille max 32 caractères (alias personnalisés)
 // LongURL : doit pas être null
 // CreateAt : Horodatage de la créatino du lien
 
 type Link struct {
-	ID        uint   `gorm:"primaryKey"`        // Clé primaire
-	ShortCode string `gorm:"uniqueIndex;size:10"` // Code court unique, indexé pour des recherches rapides, taille maximale de 10 caractères
-	LongURL   string `gorm:"not null"` // URL longue, ne peut pas être nulle
-	CreatedAt time.Time `gorm:"autoCreateTime"` // Horodatage de création, automatiquement défini par GORM
-}
\ No newline at end of file
+	ID        uint      `gorm:"primaryKey"`          // Clé primaire
+	ShortCode string    `gorm:"uniqueIndex;size:32"` // Code court unique (aléatoire ou alias personnalisé), indexé pour des recherches rapides
+	LongURL   string    `gorm:"not null"`            // URL longue, ne peut pas être nulle
+	CreatedAt time.Time `gorm:"autoCreateTime"`      // Horodatage de création, automatiquement défini par GORM
+
+	ExpiresAt *time.Time `gorm:"index"`               // Date d'expiration optionnelle (nil = pas d'expiration)
+	MaxClicks int        `gorm:"not null;default:0"`  // Budget de clics optionnel (0 = illimité)
+	Expired   bool       `gorm:"index;default:false"` // Positionné par le sweeper une fois le lien expiré ou épuisé
+
+	// Redirections décomptées du budget de clics, réservées au moment de la redirection
+	// (les clics eux-mêmes sont enregistrés plus tard, par lots). Non maintenu sans budget.
+	ReservedClicks int `gorm:"not null;default:0"`
+
+	Disabled  bool           `gorm:"index;default:false"` // Lien désactivé manuellement, la redirection est refusée
+	UpdatedAt time.Time      `gorm:"autoUpdateTime"`      // Horodatage de dernière modification
+	DeletedAt gorm.DeletedAt `gorm:"index"`               // Suppression logique (soft delete) gérée par GORM
+
+	FallbackURL  string     `gorm:"size:2048"` // URL de repli propre au lien quand sa destination est durablement inaccessible
+	DownSince    *time.Time // Début de l'inaccessibilité constatée par le moniteur (nil = destination accessible)
+	HealthAction string     `gorm:"size:16;not null;default:''"` // Mesure appliquée par le moniteur (HealthAction*), vide si aucune
+
+	RedirectType int `gorm:"not null;default:302"` // Code HTTP de la redirection : 301, 302, 307 ou 308
+}
+
+// Mesures appliquées par le moniteur aux liens dont la destination est durablement inaccessible.
+// Elles sont levées automatiquement dès que la destination redevient accessible.
+const (
+	HealthActionDisable  = "disable"  // La redirection est refusée (410 Gone)
+	HealthActionFallback = "fallback" // La redirection mène à l'URL de repli
+)
+
+// IsExpiredAt indique si la date d'expiration du lien est dépassée à l'instant donné.
+func (l *Link) IsExpiredAt(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+// HasReachedClickBudget indique si le budget du lien est atteint, d'après le nombre de clics
+// enregistrés donné ou les redirections déjà réservées (en avance sur les clics enregistrés
+// tant que le tampon d'écriture n'a pas été vidé).
+func (l *Link) HasReachedClickBudget(clicks int) bool {
+	return l.MaxClicks > 0 && max(clicks, l.ReservedClicks) >= l.MaxClicks
+}
+
+// RemainingClicks retourne le nombre de redirections encore permises par le budget du lien,
+// compté comme HasReachedClickBudget. Sans budget, elle retourne 0.
+func (l *Link) RemainingClicks(clicks int) int {
+	return max(l.MaxClicks-max(clicks, l.ReservedClicks), 0)
+}
+
+// HasEnded indique si le lien est arrivé en fin de vie (marqué expiré,
+// date d'expiration dépassée ou budget de clics atteint).
+func (l *Link) HasEnded(now time.Time, clicks int) bool {
+	return l.Expired || l.IsExpiredAt(now) || l.HasReachedClickBudget(clicks)
+}
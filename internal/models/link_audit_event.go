@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Auteurs et actions enregistrés dans le journal d'audit des liens.
+const (
+	AuditActorMonitor = "monitor" // Mesures automatiques du moniteur d'URLs
+	AuditActorAPI     = "api"     // Modifications manuelles via l'API REST
+	AuditActorCLI     = "cli"     // Modifications manuelles via la ligne de commande
+
+	AuditLinkAutoDisabled    = "auto_disabled"    // Lien désactivé car sa destination est durablement inaccessible
+	AuditLinkFallbackEnabled = "fallback_enabled" // Redirection basculée vers l'URL de repli
+	AuditLinkReinstated      = "reinstated"       // Mesure levée, la destination est de nouveau accessible
+	AuditLinkUpdated         = "updated"          // Destination, URL de repli ou type de redirection modifiés
+	AuditLinkDisabled        = "disabled"         // Lien désactivé manuellement
+	AuditLinkEnabled         = "enabled"          // Lien réactivé manuellement
+	AuditLinkDeleted         = "deleted"          // Lien supprimé
+)
+
+// LinkAuditEvent est une entrée du journal d'audit d'un lien : une modification de son
+// comportement, avec son auteur et son contexte.
+type LinkAuditEvent struct {
+	ID        uint      `gorm:"primaryKey"`
+	LinkID    uint      `gorm:"index;not null"`   // Lien concerné
+	CreatedAt time.Time `gorm:"autoCreateTime"`   // Horodatage de l'action
+	Actor     string    `gorm:"size:32;not null"` // Auteur de l'action (AuditActor*)
+	Action    string    `gorm:"size:32;not null"` // Action effectuée (AuditLink*)
+	Detail    string    `gorm:"size:512"`         // Contexte lisible (durée d'inaccessibilité, URL de repli...)
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// États d'un lien surveillé, après application du seuil d'échecs consécutifs.
+const (
+	LinkStateUp   = "up"
+	LinkStateDown = "down"
+)
+
+// Classes d'erreur d'une vérification, pour distinguer les causes d'inaccessibilité.
+const (
+	CheckErrorDNS        = "dns"         // Nom de domaine introuvable
+	CheckErrorTLS        = "tls"         // Négociation TLS impossible ou certificat invalide
+	CheckErrorTimeout    = "timeout"     // Pas de réponse dans le délai imparti
+	CheckErrorConnection = "connection"  // Connexion refusée ou interrompue
+	CheckErrorHTTP       = "http"        // Réponse HTTP 4xx ou 5xx
+	CheckErrorRedirect   = "redirect"    // Trop de redirections
+	CheckErrorInvalidURL = "invalid_url" // URL impossible à requêter
+	CheckErrorOther      = "other"       // Toute autre erreur
+)
+
+// RedirectHop est une étape de la chaîne de redirections suivie lors d'une vérification.
+type RedirectHop struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+}
+
+// LinkCheck est le résultat d'une vérification de l'URL longue d'un lien par le moniteur.
+// L'historique permet de calculer la disponibilité et de restaurer le dernier état connu au démarrage.
+type LinkCheck struct {
+	ID                  uint          `gorm:"primaryKey"`
+	LinkID              uint          `gorm:"index:idx_link_checks_link_time;not null"` // Lien vérifié
+	CheckedAt           time.Time     `gorm:"index:idx_link_checks_link_time;index"`    // Horodatage de la vérification (UTC)
+	Up                  bool          // L'URL était accessible (réponse 2xx ou 3xx)
+	StatusCode          int           // Code HTTP reçu, 0 si aucune réponse
+	LatencyMs           int64         // Durée de la vérification en millisecondes
+	Error               string        `gorm:"size:512"`        // Erreur réseau éventuelle
+	Method              string        `gorm:"size:8"`          // Méthode de la dernière requête (HEAD, ou GET en repli)
+	ErrorClass          string        `gorm:"size:16"`         // Classe d'erreur (CheckError*), vide si l'URL était accessible
+	RedirectChain       []RedirectHop `gorm:"serializer:json"` // Redirections suivies jusqu'à la réponse finale, vide sans redirection
+	State               string        `gorm:"size:8"`          // État du lien après cette vérification (LinkStateUp ou LinkStateDown)
+	ConsecutiveFailures int           // Nombre d'échecs consécutifs, cette vérification comprise
+}
+
+// LinkUp indique si le lien était considéré comme accessible après cette vérification.
+// Les vérifications enregistrées avant l'introduction de State se rabattent sur Up.
+func (c LinkCheck) LinkUp() bool {
+	if c.State == "" {
+		return c.Up
+	}
+	return c.State == LinkStateUp
+}
@@ -1,107 +1,617 @@
 package monitor
 
 import (
-	"log"
+	"cmp"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 	"sync" // Pour protéger l'accès concurrentiel à knownStates
+	"sync/atomic"
 	"time"
 
-	_ "github.com/axellelanca/urlshortener/internal/models"   // Importe les modèles de liens
+	"github.com/axellelanca/urlshortener/internal/logging"
+	"github.com/axellelanca/urlshortener/internal/metrics"
+	"github.com/axellelanca/urlshortener/internal/models" // Importe les modèles de liens
+	"github.com/axellelanca/urlshortener/internal/notify"
+	"github.com/axellelanca/urlshortener/internal/ratelimit"
 	"github.com/axellelanca/urlshortener/internal/repository" // Importe le repository de liens
 )
 
+// Config regroupe les paramètres du moniteur d'URLs.
+type Config struct {
+	Interval    time.Duration      // Intervalle entre deux passages (ex: 5 minutes)
+	Retention   time.Duration      // Durée de conservation de l'historique (0 = illimitée)
+	Concurrency int                // Nombre maximal de vérifications simultanées
+	HostLimiter *ratelimit.Limiter // Budget de vérifications par hôte de destination (nil = illimité)
+	Jitter      float64            // Part de l'intervalle (0 à 1) sur laquelle les vérifications sont étalées
+	// FailureThreshold est le nombre d'échecs consécutifs avant qu'un lien accessible soit déclaré
+	// inaccessible, pour ne pas notifier sur une panne passagère. Un succès le rétablit aussitôt.
+	FailureThreshold int
+	UserAgent        string // User-Agent des requêtes de vérification
+	DeadLink         DeadLinkPolicy
+}
+
+// DeadLinkPolicy décrit la mesure appliquée aux liens dont la destination reste inaccessible
+// trop longtemps. La mesure est levée dès que la destination redevient accessible.
+type DeadLinkPolicy struct {
+	Action      string        // models.HealthActionDisable, models.HealthActionFallback ou vide (aucune mesure)
+	After       time.Duration // Durée d'inaccessibilité avant d'appliquer la mesure
+	FallbackURL string        // URL de repli globale ; sans elle ni URL propre au lien, le lien est désactivé
+}
+
+// maxRedirects est le nombre maximal de redirections suivies lors d'une vérification.
+const maxRedirects = 10
+
+// errTooManyRedirects est retournée par le client quand la chaîne de redirections est trop longue.
+var errTooManyRedirects = errors.New("too many redirects")
+
 // UrlMonitor gère la surveillance périodique des URLs longues.
 type UrlMonitor struct {
-	linkRepo    repository.LinkRepository // Pour récupérer les URLs à surveiller
-	interval    time.Duration             // Intervalle entre chaque vérification (ex: 5 minutes)
-	knownStates map[uint]bool             // État connu de chaque URL: map[LinkID]estAccessible (true/false)
-	mu          sync.Mutex                // Mutex pour protéger l'accès concurrentiel à knownStates
+	linkRepo    repository.LinkRepository      // Pour récupérer les URLs à surveiller
+	checkRepo   repository.LinkCheckRepository // Pour historiser chaque vérification
+	notifier    notify.Notifier                // Prévenu à chaque changement d'état
+	cfg         Config                         // Intervalle, concurrence et politesse envers les hôtes
+	client      *http.Client                   // Client partagé par les vérifications
+	running     atomic.Bool                    // Un passage est en cours : empêche les chevauchements
+	knownStates map[uint]bool                  // État connu de chaque URL: map[LinkID]estAccessible (true/false)
+	failures    map[uint]int                   // Échecs consécutifs de chaque URL: map[LinkID]nombre
+	mu          sync.Mutex                     // Mutex pour protéger l'accès concurrentiel à knownStates et failures
+	logger      *slog.Logger                   // Logger annoté du composant "monitor"
+}
+
+// linkCheck associe le résultat d'une vérification au lien vérifié.
+type linkCheck struct {
+	link   models.Link
+	result checkResult
+}
+
+// scheduledLink est un lien à vérifier après un décalage depuis le début du passage.
+type scheduledLink struct {
+	link   models.Link
+	offset time.Duration
+}
+
+// checkResult est le résultat de la vérification d'une URL.
+type checkResult struct {
+	up         bool                 // Réponse 2xx ou 3xx
+	method     string               // Méthode de la dernière requête (HEAD, ou GET en repli)
+	statusCode int                  // Code HTTP reçu, 0 si aucune réponse
+	latency    time.Duration        // Durée de la vérification
+	redirects  []models.RedirectHop // Chaîne de redirections suivie, vide sans redirection
+	errorClass string               // Classe d'erreur (models.CheckError*), vide si accessible
+	err        error                // Erreur réseau ou de requête
 }
 
 // NewUrlMonitor crée et retourne une nouvelle instance de UrlMonitor.
 // Attention: retourne un pointeur
-func NewUrlMonitor(linkRepo repository.LinkRepository, interval time.Duration) *UrlMonitor {
+// Une concurrence ou un seuil d'échecs inférieur à 1 est ramené à 1.
+func NewUrlMonitor(linkRepo repository.LinkRepository, checkRepo repository.LinkCheckRepository,
+	notifier notify.Notifier, cfg Config) *UrlMonitor {
+	cfg.Concurrency = max(cfg.Concurrency, 1)
+	cfg.FailureThreshold = max(cfg.FailureThreshold, 1)
+	cfg.Jitter = min(max(cfg.Jitter, 0), 1)
 	return &UrlMonitor{
-		linkRepo:    linkRepo,
-		interval:    interval,
+		linkRepo:  linkRepo,
+		checkRepo: checkRepo,
+		notifier:  notifier,
+		cfg:       cfg,
+		// Définir un timeout pour éviter de bloquer trop longtemps (5 secondes c'est bien)
+		client: &http.Client{
+			Timeout: 5 * time.Second,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return errTooManyRedirects
+				}
+				return nil
+			},
+		},
 		knownStates: make(map[uint]bool),
+		failures:    make(map[uint]int),
+		logger:      logging.Component("monitor"),
 	}
 }
 
 // Start lance la boucle de surveillance périodique des URLs.
-// Cette fonction est conçue pour être lancée dans une goroutine séparée.
-func (m *UrlMonitor) Start() {
-	log.Printf("[MONITOR] Démarrage du moniteur d'URLs avec un intervalle de %v...", m.interval)
-	ticker := time.NewTicker(m.interval) // Crée un ticker qui envoie un signal à chaque intervalle
-	defer ticker.Stop()                  // S'assure que le ticker est arrêté quand Start se termine
+// Cette fonction est conçue pour être lancée dans une goroutine séparée ;
+// elle se termine quand ctx est annulé, y compris au milieu d'une vérification,
+// après la fin du passage en cours.
+func (m *UrlMonitor) Start(ctx context.Context) {
+	m.logger.Info("Démarrage du moniteur d'URLs", "interval", m.cfg.Interval.String(),
+		"concurrency", m.cfg.Concurrency, "jitter", m.cfg.Jitter)
+	ticker := time.NewTicker(m.cfg.Interval) // Crée un ticker qui envoie un signal à chaque intervalle
+	defer ticker.Stop()                      // S'assure que le ticker est arrêté quand Start se termine
+
+	// Reprendre les derniers états enregistrés pour qu'un redémarrage ne masque pas les transitions.
+	m.restoreStates()
+
+	var passes sync.WaitGroup
+	defer passes.Wait()
 
 	// Exécute une première vérification immédiatement au démarrage
-	m.checkUrls()
+	m.startPass(ctx, &passes)
 
 	// Boucle principale du moniteur, déclenchée par le ticker
-	for range ticker.C {
-		m.checkUrls()
+	for {
+		select {
+		case <-ctx.Done():
+			m.logger.Info("Arrêt du moniteur d'URLs")
+			return
+		case <-ticker.C:
+			m.startPass(ctx, &passes)
+		}
 	}
 }
 
+// startPass lance un passage dans sa propre goroutine, sauf si le précédent n'est pas
+// terminé : deux passages ne s'exécutent jamais en même temps.
+func (m *UrlMonitor) startPass(ctx context.Context, passes *sync.WaitGroup) {
+	if !m.running.CompareAndSwap(false, true) {
+		m.logger.Warn("Passage précédent toujours en cours, vérification ignorée",
+			"interval", m.cfg.Interval.String())
+		metrics.MonitorPassesSkipped.Inc()
+		return
+	}
+	passes.Add(1)
+	go func() {
+		defer passes.Done()
+		defer m.running.Store(false)
+		m.checkUrls(ctx)
+	}()
+}
+
 // checkUrls effectue une vérification de l'état de toutes les URLs longues enregistrées.
-func (m *UrlMonitor) checkUrls() {
-	log.Println("[MONITOR] Lancement de la vérification de l'état des URLs...")
+// Les vérifications sont confiées à un pool de cfg.Concurrency workers et étalées sur
+// une partie de l'intervalle ; les résultats sont traités ici, un par un, pour que
+// l'historique et les notifications ne subissent pas de concurrence.
+func (m *UrlMonitor) checkUrls(ctx context.Context) {
+	m.logger.Info("Lancement de la vérification de l'état des URLs")
+	passStart := time.Now()
 
-	// Récupérer toutes les URLs longues actives depuis le linkRepo (GetAllLinks).
+	// Récupérer toutes les URLs longues actives depuis le linkRepo (GetActiveLinks).
+	// Les liens expirés sont exclus de la surveillance.
 	// Gérer l'erreur si la récupération échoue.
-	links, err := m.linkRepo.GetAllLinks()
+	links, err := m.linkRepo.GetActiveLinks()
 	if err != nil {
-		log.Printf("[MONITOR] ERREUR lors de la récupération des liens pour la surveillance : %v", err)
+		m.logger.Error("Échec de la récupération des liens à surveiller", "error", err)
 		return
 	}
 
-	for _, link := range links {
-		// Pour chaque lien, vérifier son accessibilité (isUrlAccessible).
-		currentState := m.isUrlAccessible(link.LongURL)
+	jobs := make(chan models.Link)
+	results := make(chan linkCheck)
+	var workers sync.WaitGroup
+	for range min(m.cfg.Concurrency, max(len(links), 1)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for link := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				// Pour chaque lien, vérifier son accessibilité (checkUrl).
+				results <- linkCheck{link: link, result: m.checkUrl(ctx, link.LongURL)}
+			}
+		}()
+	}
+	go func() {
+		m.dispatch(ctx, links, jobs)
+		close(jobs)
+		workers.Wait()
+		close(results)
+	}()
 
-		// Protéger l'accès à la map 'knownStates' car 'checkUrls' peut être exécuté concurremment
+	up, down := 0, 0
+	for check := range results {
+		if ctx.Err() != nil {
+			// Vérification coupée par l'arrêt du service : son résultat n'est pas significatif.
+			continue
+		}
+		link, result := check.link, check.result
+		metrics.MonitorCheckDuration.Observe(result.latency.Seconds())
+		if result.errorClass != "" {
+			metrics.MonitorCheckErrors.WithLabelValues(result.errorClass).Inc()
+		}
+
+		// Protéger l'accès aux maps 'knownStates' et 'failures', aussi modifiées par restoreStates
 		m.mu.Lock()
 		previousState, exists := m.knownStates[link.ID] // Récupère l'état précédent
-		m.knownStates[link.ID] = currentState           // Met à jour l'état actuel
+		failures := 0
+		if !result.up {
+			failures = m.failures[link.ID] + 1
+		}
+		// Un lien accessible ne devient inaccessible qu'après FailureThreshold échecs consécutifs ;
+		// un lien jamais vérifié prend directement l'état constaté.
+		currentState := previousState
+		if result.up || !exists || failures >= m.cfg.FailureThreshold {
+			currentState = result.up
+		}
+		m.knownStates[link.ID] = currentState // Met à jour l'état actuel
+		m.failures[link.ID] = failures
 		m.mu.Unlock()
 
+		m.recordCheck(link.ID, result, currentState, failures)
+		m.applyDeadLinkPolicy(link, currentState)
+		if currentState {
+			up++
+		} else {
+			down++
+		}
+		if currentState && !result.up {
+			m.logger.Info("Échec de vérification, en attente de confirmation",
+				"short_code", link.ShortCode, "error_class", result.errorClass,
+				"failures", failures, "threshold", m.cfg.FailureThreshold)
+		}
+
 		// Si c'est la première vérification pour ce lien, on initialise l'état sans notifier.
 		if !exists {
-			log.Printf("[MONITOR] État initial pour le lien %s (%s) : %s",
-				link.ShortCode, link.LongURL, formatState(currentState))
+			m.logger.Info("État initial du lien",
+				"short_code", link.ShortCode, "long_url", link.LongURL, "state", formatState(currentState))
 			continue
 		}
 
 		// Comparer l'état actuel avec l'état précédent.
-		// Si l'état a changé, générer une fausse notification dans les logs.
+		// Si l'état a changé, prévenir les canaux de notification configurés.
 		if currentState != previousState {
-			log.Printf("[NOTIFICATION] Le lien %s (%s) est passé de %s à %s !",
-				link.ShortCode, link.LongURL, formatState(previousState), formatState(currentState))
+			m.notifyStateChange(ctx, link, previousState, currentState, result)
+		}
+	}
+	// Interrompre la vérification si l'arrêt du service a été demandé.
+	if ctx.Err() != nil {
+		m.logger.Info("Vérification interrompue")
+		return
+	}
+	// Les jauges ne sont mises à jour que pour un passage complet.
+	metrics.MonitorLinks.WithLabelValues("up").Set(float64(up))
+	metrics.MonitorLinks.WithLabelValues("down").Set(float64(down))
+	metrics.MonitorPassDuration.Observe(time.Since(passStart).Seconds())
+	m.logger.Info("Vérification de l'état des URLs terminée", "up", up, "down", down)
+
+	m.purgeHistory()
+}
+
+// applyDeadLinkPolicy tient à jour le début d'inaccessibilité d'un lien et applique ou lève
+// la mesure de DeadLinkPolicy. link est l'état du lien lu au début du passage ; chaque
+// mesure est enregistrée dans le journal d'audit.
+func (m *UrlMonitor) applyDeadLinkPolicy(link models.Link, up bool) {
+	now := time.Now().UTC()
+	var event *models.LinkAuditEvent
+
+	switch {
+	case up && link.DownSince == nil && link.HealthAction == "":
+		return
+	case up:
+		// Destination de nouveau accessible : lever la mesure éventuelle.
+		if link.HealthAction != "" {
+			detail := "destination de nouveau accessible"
+			if link.DownSince != nil {
+				detail += fmt.Sprintf(" après %s", now.Sub(*link.DownSince).Round(time.Minute))
+			}
+			event = &models.LinkAuditEvent{LinkID: link.ID, Actor: models.AuditActorMonitor,
+				Action: models.AuditLinkReinstated, Detail: detail}
 		}
+		link.DownSince = nil
+		link.HealthAction = ""
+	case link.DownSince == nil:
+		link.DownSince = &now
+	case link.HealthAction == "" && m.cfg.DeadLink.Action != "" && now.Sub(*link.DownSince) >= m.cfg.DeadLink.After:
+		event = m.deadLinkEvent(&link, now)
+	default:
+		return
+	}
+
+	if err := m.linkRepo.UpdateLinkHealth(&link, event); err != nil {
+		m.logger.Error("Échec de la mise à jour de l'état de santé du lien", "short_code", link.ShortCode, "error", err)
+		return
+	}
+	if event != nil {
+		metrics.MonitorDeadLinkActions.WithLabelValues(event.Action).Inc()
+		m.logger.Warn("Mesure appliquée à un lien", "short_code", link.ShortCode,
+			"action", event.Action, "detail", event.Detail)
+	}
+}
+
+// deadLinkEvent applique à link la mesure configurée pour une destination durablement
+// inaccessible et retourne l'entrée d'audit correspondante.
+func (m *UrlMonitor) deadLinkEvent(link *models.Link, now time.Time) *models.LinkAuditEvent {
+	downFor := now.Sub(*link.DownSince).Round(time.Minute)
+	event := &models.LinkAuditEvent{LinkID: link.ID, Actor: models.AuditActorMonitor}
+
+	fallbackURL := link.FallbackURL
+	if fallbackURL == "" {
+		fallbackURL = m.cfg.DeadLink.FallbackURL
+	}
+	if m.cfg.DeadLink.Action == models.HealthActionFallback && fallbackURL != "" {
+		link.HealthAction = models.HealthActionFallback
+		event.Action = models.AuditLinkFallbackEnabled
+		event.Detail = truncate(fmt.Sprintf("destination inaccessible depuis %s, redirection vers %s", downFor, fallbackURL), 512)
+		return event
+	}
+
+	link.HealthAction = models.HealthActionDisable
+	event.Action = models.AuditLinkAutoDisabled
+	event.Detail = fmt.Sprintf("destination inaccessible depuis %s", downFor)
+	if m.cfg.DeadLink.Action == models.HealthActionFallback {
+		event.Detail += ", aucune URL de repli configurée"
 	}
-	log.Println("[MONITOR] Vérification de l'état des URLs terminée.")
+	return event
 }
 
-// isUrlAccessible effectue une requête HTTP HEAD pour vérifier l'accessibilité d'une URL.
-func (m *UrlMonitor) isUrlAccessible(url string) bool {
-	// Définir un timeout pour éviter de bloquer trop longtemps (5 secondes c'est bien)
-	client := &http.Client{
-		Timeout: 5 * time.Second,
+// dispatch transmet les liens aux workers en les étalant aléatoirement sur la fenêtre
+// cfg.Jitter × cfg.Interval, pour lisser la charge au lieu de tout vérifier d'un coup.
+// Un lien dont l'hôte a épuisé son budget est reprogrammé à la fin de l'attente imposée,
+// sans occuper de worker : les liens des autres hôtes passent devant.
+// Il s'arrête dès que ctx est annulé.
+func (m *UrlMonitor) dispatch(ctx context.Context, links []models.Link, jobs chan<- models.Link) {
+	window := time.Duration(m.cfg.Jitter * float64(m.cfg.Interval))
+	schedule := make([]scheduledLink, len(links))
+	for i, link := range links {
+		schedule[i].link = link
+		if window > 0 {
+			schedule[i].offset = rand.N(window)
+		}
 	}
+	slices.SortFunc(schedule, func(a, b scheduledLink) int { return cmp.Compare(a.offset, b.offset) })
 
-	// Effectuer une requête HEAD (plus légère que GET) sur l'URL.
-	resp, err := client.Head(url)
+	start := time.Now()
+	for len(schedule) > 0 {
+		item := schedule[0]
+		schedule = schedule[1:]
+		if !sleepContext(ctx, time.Until(start.Add(item.offset))) {
+			return
+		}
+		if delay := m.hostDelay(item.link.LongURL); delay > 0 {
+			metrics.MonitorHostThrottled.Inc()
+			item.offset = time.Since(start) + delay
+			i, _ := slices.BinarySearchFunc(schedule, item.offset, func(s scheduledLink, offset time.Duration) int {
+				return cmp.Compare(s.offset, offset)
+			})
+			schedule = slices.Insert(schedule, i, item)
+			continue
+		}
+		select {
+		case jobs <- item.link:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// hostDelay consomme le budget de l'hôte de rawURL pour une vérification. Il retourne 0 si
+// la vérification peut avoir lieu tout de suite, sinon l'attente avant le prochain essai.
+// Une erreur du budget laisse passer la vérification.
+func (m *UrlMonitor) hostDelay(rawURL string) time.Duration {
+	if m.cfg.HostLimiter == nil {
+		return 0
+	}
+	parsed, err := url.Parse(rawURL)
 	if err != nil {
-		log.Printf("[MONITOR] Erreur d'accès à l'URL '%s': %v", url, err)
+		return 0
+	}
+	result, err := m.cfg.HostLimiter.Allow(strings.ToLower(parsed.Hostname()))
+	if err != nil || result.Allowed {
+		return 0
+	}
+	return max(result.RetryAfter, time.Millisecond)
+}
+
+// sleepContext attend la durée d ou l'annulation de ctx ; il retourne false dans ce dernier cas.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
 		return false
 	}
+}
+
+// notifyStateChange envoie l'événement de changement d'état d'un lien aux notifiers.
+func (m *UrlMonitor) notifyStateChange(ctx context.Context, link models.Link, previousState, currentState bool, result checkResult) {
+	event := notify.Event{
+		Type:          notify.EventLinkStateChanged,
+		LinkID:        link.ID,
+		ShortCode:     link.ShortCode,
+		LongURL:       link.LongURL,
+		PreviousState: notifyState(previousState),
+		State:         notifyState(currentState),
+		StatusCode:    result.statusCode,
+		ErrorClass:    result.errorClass,
+		OccurredAt:    time.Now().UTC(),
+	}
+	if result.err != nil {
+		event.Error = result.err.Error()
+	}
+	if err := m.notifier.Notify(ctx, event); err != nil {
+		m.logger.Error("Échec de l'envoi d'une notification", "short_code", link.ShortCode, "error", err)
+	}
+}
+
+// notifyState convertit un état d'accessibilité en état d'événement de notification.
+func notifyState(accessible bool) string {
+	if accessible {
+		return notify.StateUp
+	}
+	return notify.StateDown
+}
+
+// linkState convertit un état d'accessibilité en état enregistré dans l'historique.
+func linkState(accessible bool) string {
+	if accessible {
+		return models.LinkStateUp
+	}
+	return models.LinkStateDown
+}
 
+// restoreStates recharge dans knownStates et failures l'état enregistré lors de la dernière
+// vérification de chaque lien.
+func (m *UrlMonitor) restoreStates() {
+	checks, err := m.checkRepo.GetLatestChecks()
+	if err != nil {
+		m.logger.Error("Échec de la restauration des derniers états connus", "error", err)
+		return
+	}
+	m.mu.Lock()
+	for linkID, check := range checks {
+		m.knownStates[linkID] = check.LinkUp()
+		m.failures[linkID] = check.ConsecutiveFailures
+	}
+	m.mu.Unlock()
+	m.logger.Info("Derniers états connus restaurés", "links", len(checks))
+}
+
+// recordCheck historise le résultat d'une vérification et l'état du lien qui en découle.
+// Un échec d'écriture est journalisé sans interrompre la surveillance.
+func (m *UrlMonitor) recordCheck(linkID uint, result checkResult, state bool, failures int) {
+	check := &models.LinkCheck{
+		LinkID:              linkID,
+		CheckedAt:           time.Now().UTC(),
+		Up:                  result.up,
+		StatusCode:          result.statusCode,
+		LatencyMs:           result.latency.Milliseconds(),
+		Method:              result.method,
+		ErrorClass:          result.errorClass,
+		RedirectChain:       result.redirects,
+		State:               linkState(state),
+		ConsecutiveFailures: failures,
+	}
+	if result.err != nil {
+		check.Error = truncate(result.err.Error(), 512)
+	}
+	if err := m.checkRepo.CreateLinkCheck(check); err != nil {
+		m.logger.Error("Échec de l'enregistrement de la vérification", "link_id", linkID, "error", err)
+	}
+}
+
+// purgeHistory supprime les vérifications plus anciennes que la durée de conservation.
+func (m *UrlMonitor) purgeHistory() {
+	if m.cfg.Retention <= 0 {
+		return
+	}
+	deleted, err := m.checkRepo.DeleteChecksBefore(time.Now().Add(-m.cfg.Retention))
+	if err != nil {
+		m.logger.Error("Échec de la purge de l'historique des vérifications", "error", err)
+		return
+	}
+	if deleted > 0 {
+		m.logger.Info("Historique des vérifications purgé", "deleted", deleted)
+	}
+}
+
+// checkUrl vérifie l'accessibilité d'une URL avec une requête HEAD (plus légère que GET).
+// Beaucoup de serveurs refusent HEAD (403, 405...) tout en servant la page normalement :
+// en cas de réponse 4xx ou 5xx, la vérification est refaite avec un GET limité au premier octet.
+func (m *UrlMonitor) checkUrl(ctx context.Context, url string) checkResult {
+	start := time.Now()
+	result := m.probe(ctx, http.MethodHead, url)
+	if result.statusCode >= 400 && ctx.Err() == nil {
+		result = m.probe(ctx, http.MethodGet, url)
+	}
+	result.latency = time.Since(start)
+	return result
+}
+
+// probe envoie une requête de vérification et classe son résultat. Les redirections sont
+// suivies (jusqu'à maxRedirects) et enregistrées.
+func (m *UrlMonitor) probe(ctx context.Context, method, url string) checkResult {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		m.logger.Warn("URL invalide", "url", url, "error", err)
+		return checkResult{method: method, errorClass: models.CheckErrorInvalidURL, err: err}
+	}
+	if m.cfg.UserAgent != "" {
+		req.Header.Set("User-Agent", m.cfg.UserAgent)
+	}
+	if method == http.MethodGet {
+		// Seul le premier octet est demandé : la réponse suffit à juger de l'accessibilité.
+		req.Header.Set("Range", "bytes=0-0")
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		m.logger.Debug("Erreur d'accès à l'URL", "url", url, "method", method, "error", err)
+		return checkResult{method: method, errorClass: classifyError(err), err: err}
+	}
 	// Assurez-vous de fermer le corps de la réponse pour libérer les ressources
 	defer resp.Body.Close()
 
 	// Déterminer l'accessibilité basée sur le code de statut HTTP.
-	return resp.StatusCode >= 200 && resp.StatusCode < 400 // Codes 2xx ou 3xx
+	// 416 signifie que la ressource existe mais qu'elle est vide : la plage demandée n'existe pas.
+	up := resp.StatusCode >= 200 && resp.StatusCode < 400 || // Codes 2xx ou 3xx
+		method == http.MethodGet && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable
+	result := checkResult{
+		up:         up,
+		method:     method,
+		statusCode: resp.StatusCode,
+		redirects:  redirectChain(resp),
+	}
+	if !up {
+		result.errorClass = models.CheckErrorHTTP
+	}
+	return result
+}
+
+// redirectChain reconstitue les redirections suivies pour obtenir resp, de la première URL
+// à la réponse finale incluse. Elle retourne nil quand il n'y a eu aucune redirection.
+func redirectChain(resp *http.Response) []models.RedirectHop {
+	if resp.Request == nil || resp.Request.Response == nil {
+		return nil
+	}
+	chain := []models.RedirectHop{{URL: resp.Request.URL.String(), StatusCode: resp.StatusCode}}
+	for redirect := resp.Request.Response; redirect != nil; redirect = redirect.Request.Response {
+		chain = append(chain, models.RedirectHop{URL: redirect.Request.URL.String(), StatusCode: redirect.StatusCode})
+	}
+	slices.Reverse(chain)
+	return chain
+}
+
+// classifyError associe une erreur de requête à une classe (models.CheckError*).
+func classifyError(err error) string {
+	var (
+		dnsErr     *net.DNSError
+		certErr    *tls.CertificateVerificationError
+		recordErr  tls.RecordHeaderError
+		alertErr   tls.AlertError
+		authErr    x509.UnknownAuthorityError
+		hostErr    x509.HostnameError
+		invalidErr x509.CertificateInvalidError
+		netErr     net.Error
+		opErr      *net.OpError
+	)
+	switch {
+	case errors.Is(err, errTooManyRedirects):
+		return models.CheckErrorRedirect
+	case errors.As(err, &dnsErr):
+		return models.CheckErrorDNS
+	case errors.As(err, &certErr), errors.As(err, &recordErr), errors.As(err, &alertErr),
+		errors.As(err, &authErr), errors.As(err, &hostErr), errors.As(err, &invalidErr):
+		return models.CheckErrorTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return models.CheckErrorTimeout
+	case errors.As(err, &opErr):
+		return models.CheckErrorConnection
+	default:
+		return models.CheckErrorOther
+	}
+}
+
+// truncate limite s à max octets pour respecter la taille des colonnes.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return strings.ToValidUTF8(s[:max], "")
 }
 
 // formatState est une fonction utilitaire pour rendre l'état plus lisible dans les logs.
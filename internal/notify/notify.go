@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/logging"
+)
+
+// Types d'événements notifiés.
+const (
+	EventLinkStateChanged = "link.state_changed" // La destination d'un lien est devenue (in)accessible
+)
+
+// États d'une destination dans les événements.
+const (
+	StateUp   = "up"
+	StateDown = "down"
+)
+
+// Event décrit un événement à notifier. Il est sérialisé tel quel dans les payloads JSON.
+type Event struct {
+	Type          string    `json:"type"`
+	LinkID        uint      `json:"link_id"`
+	ShortCode     string    `json:"short_code"`
+	LongURL       string    `json:"long_url"`
+	PreviousState string    `json:"previous_state"`
+	State         string    `json:"state"`
+	StatusCode    int       `json:"status_code,omitempty"` // Code HTTP de la vérification, 0 si aucune réponse
+	ErrorClass    string    `json:"error_class,omitempty"` // Classe d'erreur de la vérification (dns, tls, timeout, http...)
+	Error         string    `json:"error,omitempty"`       // Erreur réseau de la vérification
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+// Notifier envoie un événement vers un canal de notification (log, webhook...).
+// Notify doit respecter l'annulation de ctx et peut être appelée en concurrence.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// Multi diffuse chaque événement à plusieurs notifiers en parallèle.
+// L'échec d'un canal n'empêche pas les autres d'être notifiés ; les erreurs sont regroupées.
+type Multi []Notifier
+
+// Notify implémente Notifier.
+func (m Multi) Notify(ctx context.Context, event Event) error {
+	errs := make([]error, len(m))
+	var wg sync.WaitGroup
+	for i, n := range m {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = n.Notify(ctx, event)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// LogNotifier écrit les événements dans les logs ; c'est le canal toujours actif.
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+// NewLogNotifier crée un LogNotifier écrivant dans le logger par défaut.
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{logger: logging.Component("notify")}
+}
+
+// Notify implémente Notifier.
+func (n *LogNotifier) Notify(ctx context.Context, event Event) error {
+	n.logger.WarnContext(ctx, "[NOTIFICATION] Changement d'état du lien",
+		"event", event.Type,
+		"short_code", event.ShortCode, "long_url", event.LongURL,
+		"previous_state", event.PreviousState, "state", event.State)
+	return nil
+}
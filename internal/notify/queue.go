@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/axellelanca/urlshortener/internal/logging"
+	"github.com/axellelanca/urlshortener/internal/metrics"
+)
+
+// ErrQueueFull est retournée par Queue.Notify quand la file est pleine : l'événement est abandonné.
+var ErrQueueFull = errors.New("notification queue is full")
+
+// Queue découple l'émetteur des événements de leur livraison : Notify se contente de mettre
+// l'événement en file et Run le livre ensuite au notifier sous-jacent. La file est bornée ;
+// une fois pleine, les nouveaux événements sont abandonnés plutôt que de bloquer l'émetteur
+// (par exemple le moniteur, pendant qu'un webhook lent est retenté).
+type Queue struct {
+	next   Notifier
+	events chan Event
+	logger *slog.Logger
+}
+
+// NewQueue crée une file de size événements devant next. Une taille inférieure à 1 est ramenée à 1.
+func NewQueue(next Notifier, size int) *Queue {
+	return &Queue{
+		next:   next,
+		events: make(chan Event, max(size, 1)),
+		logger: logging.Component("notify"),
+	}
+}
+
+// Notify implémente Notifier. Elle ne bloque jamais : l'événement est mis en file,
+// ou abandonné avec ErrQueueFull si la file est pleine.
+func (q *Queue) Notify(_ context.Context, event Event) error {
+	select {
+	case q.events <- event:
+		return nil
+	default:
+		metrics.NotificationsDropped.Inc()
+		return ErrQueueFull
+	}
+}
+
+// Run livre les événements en file, un par un, jusqu'à l'annulation de ctx.
+// Les événements encore en file à l'arrêt ne sont pas livrés.
+func (q *Queue) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			if pending := len(q.events); pending > 0 {
+				q.logger.Warn("Arrêt de la file de notifications, événements non livrés", "pending_events", pending)
+			}
+			return
+		case event := <-q.events:
+			if err := q.next.Notify(ctx, event); err != nil {
+				q.logger.Error("Échec de la livraison d'une notification",
+					"event", event.Type, "short_code", event.ShortCode, "error", err)
+			}
+		}
+	}
+}
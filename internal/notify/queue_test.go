@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockingNotifier bloque chaque livraison jusqu'à la fermeture de release.
+type blockingNotifier struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (n *blockingNotifier) Notify(ctx context.Context, _ Event) error {
+	n.started <- struct{}{}
+	select {
+	case <-n.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestQueueDropsEventsWhenFull(t *testing.T) {
+	slow := &blockingNotifier{started: make(chan struct{}, 10), release: make(chan struct{})}
+	queue := NewQueue(slow, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		queue.Run(ctx)
+	}()
+
+	// Le premier événement est en cours de livraison, le deuxième remplit la file.
+	if err := queue.Notify(context.Background(), testEvent); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	select {
+	case <-slow.started:
+	case <-time.After(time.Second):
+		t.Fatalf("first event was not delivered")
+	}
+	if err := queue.Notify(context.Background(), testEvent); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+
+	// La file est pleine : Notify ne bloque pas et abandonne l'événement.
+	if err := queue.Notify(context.Background(), testEvent); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("notify on full queue = %v, want ErrQueueFull", err)
+	}
+
+	close(slow.release)
+	select {
+	case <-slow.started:
+	case <-time.After(time.Second):
+		t.Fatalf("queued event was not delivered")
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,157 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	mathrand "math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/logging"
+)
+
+// En-têtes HTTP des livraisons de webhooks.
+const (
+	HeaderEvent     = "X-Webhook-Event"     // Type de l'événement
+	HeaderDelivery  = "X-Webhook-Delivery"  // Identifiant unique de la livraison, identique entre les tentatives
+	HeaderTimestamp = "X-Webhook-Timestamp" // Horodatage Unix (secondes) inclus dans la signature
+	HeaderSignature = "X-Webhook-Signature" // "sha256=" + HMAC-SHA256 hexadécimal de "<timestamp>.<corps>"
+)
+
+// ErrPermanent signale une livraison refusée par le destinataire (4xx), qui n'est pas retentée.
+var ErrPermanent = errors.New("webhook delivery rejected")
+
+// WebhookConfig configure un WebhookNotifier.
+type WebhookConfig struct {
+	URL          string        // Adresse du destinataire
+	Secret       string        // Secret partagé de la signature HMAC (vide = pas de signature)
+	Timeout      time.Duration // Délai maximal d'une tentative
+	MaxRetries   int           // Nouvelles tentatives après un échec temporaire
+	RetryBackoff time.Duration // Délai initial entre deux tentatives (doublé à chaque échec)
+}
+
+// WebhookNotifier envoie les événements en JSON, par POST, à une URL configurée.
+// Les échecs temporaires (réseau, 5xx, 429) sont retentés avec un délai exponentiel.
+type WebhookNotifier struct {
+	cfg    WebhookConfig
+	client *http.Client
+	logger *slog.Logger
+}
+
+// NewWebhookNotifier crée un WebhookNotifier. Si client est nil, un client dédié utilisant
+// cfg.Timeout est créé ; un client fourni permet par exemple de viser un serveur httptest.
+func NewWebhookNotifier(cfg WebhookConfig, client *http.Client) *WebhookNotifier {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = 500 * time.Millisecond
+	}
+	if client == nil {
+		client = &http.Client{Timeout: cfg.Timeout}
+	}
+	return &WebhookNotifier{
+		cfg:    cfg,
+		client: client,
+		logger: logging.Component("notify").With("webhook", cfg.URL),
+	}
+}
+
+// Notify implémente Notifier.
+func (n *WebhookNotifier) Notify(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	delivery := newDeliveryID()
+
+	backoff := n.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := n.deliver(ctx, event.Type, delivery, body)
+		if err == nil {
+			n.logger.Info("Webhook livré", "event", event.Type, "delivery", delivery, "attempt", attempt+1)
+			return nil
+		}
+		if errors.Is(err, ErrPermanent) || attempt >= n.cfg.MaxRetries || ctx.Err() != nil {
+			return fmt.Errorf("webhook %s after %d attempt(s): %w", n.cfg.URL, attempt+1, err)
+		}
+
+		// Délai exponentiel avec gigue.
+		wait := backoff + time.Duration(mathrand.Int64N(int64(backoff)+1))
+		n.logger.Warn("Livraison du webhook en échec, nouvelle tentative",
+			"event", event.Type, "delivery", delivery, "attempt", attempt+1, "retry_in", wait.String(), "error", err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("webhook %s: %w", n.cfg.URL, ctx.Err())
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// deliver effectue une tentative de livraison.
+func (n *WebhookNotifier) deliver(ctx context.Context, eventType, delivery string, body []byte) error {
+	reqCtx, cancel := context.WithTimeout(ctx, n.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, n.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "urlshortener-webhook/1.0")
+	req.Header.Set(HeaderEvent, eventType)
+	req.Header.Set(HeaderDelivery, delivery)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	if n.cfg.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(n.cfg.Secret, timestamp, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // Permet la réutilisation de la connexion
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("receiver responded %d", resp.StatusCode)
+	default:
+		return fmt.Errorf("%w: receiver responded %d", ErrPermanent, resp.StatusCode)
+	}
+}
+
+// Sign calcule la valeur de l'en-tête X-Webhook-Signature pour un corps et un horodatage.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature vérifie, côté destinataire, la signature d'une livraison en temps constant.
+func VerifySignature(secret, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// newDeliveryID génère un identifiant aléatoire de livraison.
+func newDeliveryID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
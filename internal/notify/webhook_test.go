@@ -0,0 +1,155 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testEvent est l'événement envoyé par les tests.
+var testEvent = Event{
+	Type:          EventLinkStateChanged,
+	LinkID:        42,
+	ShortCode:     "abc123",
+	LongURL:       "https://example.com",
+	PreviousState: StateUp,
+	State:         StateDown,
+	OccurredAt:    time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC),
+}
+
+// newTestWebhook crée un WebhookNotifier visant server, avec des délais de retry négligeables.
+func newTestWebhook(server *httptest.Server, secret string, maxRetries int) *WebhookNotifier {
+	return NewWebhookNotifier(WebhookConfig{
+		URL:          server.URL,
+		Secret:       secret,
+		Timeout:      time.Second,
+		MaxRetries:   maxRetries,
+		RetryBackoff: time.Millisecond,
+	}, server.Client())
+}
+
+func TestWebhookSignature(t *testing.T) {
+	const secret = "s3cret"
+	var verified atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get(HeaderTimestamp)
+		signature := r.Header.Get(HeaderSignature)
+
+		var event Event
+		if err := json.Unmarshal(body, &event); err != nil || event.ShortCode != testEvent.ShortCode {
+			t.Errorf("unexpected payload %s: %v", body, err)
+		}
+		if r.Header.Get(HeaderEvent) != EventLinkStateChanged || r.Header.Get(HeaderDelivery) == "" {
+			t.Errorf("missing event headers: %v", r.Header)
+		}
+		if timestamp == "" || signature != Sign(secret, timestamp, body) {
+			t.Errorf("signature %q does not match timestamp %q", signature, timestamp)
+		}
+		verified.Store(VerifySignature(secret, timestamp, body, signature) &&
+			!VerifySignature("other", timestamp, body, signature) &&
+			!VerifySignature(secret, timestamp, append(body, ' '), signature))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := newTestWebhook(server, secret, 0).Notify(context.Background(), testEvent); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if !verified.Load() {
+		t.Fatalf("VerifySignature did not accept the delivery signature only")
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	cases := []struct {
+		name     string
+		status   int
+		attempts int32 // Tentatives attendues
+		wantErr  bool
+	}{
+		{name: "server error then success", status: http.StatusInternalServerError, attempts: 3},
+		{name: "too many requests then success", status: http.StatusTooManyRequests, attempts: 3},
+		{name: "client error is not retried", status: http.StatusBadRequest, attempts: 1, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			deliveries := make(map[string]bool)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				deliveries[r.Header.Get(HeaderDelivery)] = true
+				// Les deux premières tentatives échouent, la troisième réussit.
+				if attempts.Add(1) < 3 {
+					w.WriteHeader(tc.status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			err := newTestWebhook(server, "", 5).Notify(context.Background(), testEvent)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("notify error = %v, want error: %v", err, tc.wantErr)
+			}
+			if tc.wantErr && !errors.Is(err, ErrPermanent) {
+				t.Fatalf("notify error = %v, want ErrPermanent", err)
+			}
+			if got := attempts.Load(); got != tc.attempts {
+				t.Fatalf("got %d attempt(s), want %d", got, tc.attempts)
+			}
+			if len(deliveries) != 1 {
+				t.Fatalf("delivery ID changed between attempts: %v", deliveries)
+			}
+		})
+	}
+}
+
+func TestWebhookGivesUpAfterMaxRetries(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	if err := newTestWebhook(server, "", 2).Notify(context.Background(), testEvent); err == nil {
+		t.Fatalf("notify succeeded, want an error")
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Fatalf("got %d attempt(s), want 3", got)
+	}
+}
+
+// recordingNotifier enregistre les événements reçus et retourne err.
+type recordingNotifier struct {
+	received atomic.Int32
+	err      error
+}
+
+func (n *recordingNotifier) Notify(context.Context, Event) error {
+	n.received.Add(1)
+	return n.err
+}
+
+func TestMultiContinuesWhenOneNotifierFails(t *testing.T) {
+	failure := errors.New("boom")
+	failing := &recordingNotifier{err: failure}
+	first, last := &recordingNotifier{}, &recordingNotifier{}
+
+	err := Multi{first, failing, last}.Notify(context.Background(), testEvent)
+	if !errors.Is(err, failure) {
+		t.Fatalf("multi error = %v, want %v", err, failure)
+	}
+	for i, n := range []*recordingNotifier{first, failing, last} {
+		if n.received.Load() != 1 {
+			t.Fatalf("notifier %d received %d event(s), want 1", i, n.received.Load())
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// cleanupInterval est l'intervalle minimal entre deux purges des seaux inactifs.
+const cleanupInterval = time.Minute
+
+// MemoryStore est un Store en mémoire, propre au processus et sûr en concurrence.
+// Les seaux redevenus pleins sont purgés périodiquement pour borner la mémoire.
+type MemoryStore struct {
+	mu          sync.Mutex
+	buckets     map[string]*bucket
+	lastCleanup time.Time
+}
+
+// NewMemoryStore crée un Store en mémoire vide.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:     make(map[string]*bucket),
+		lastCleanup: time.Now(),
+	}
+}
+
+// Take implémente Store.
+func (s *MemoryStore) Take(key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastCleanup) >= cleanupInterval {
+		s.cleanup(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok || b.limit != limit {
+		// Nouveau client (ou budget modifié) : le seau démarre plein.
+		b = &bucket{tokens: float64(limit.Burst), last: now, limit: limit}
+		s.buckets[key] = b
+	}
+	return b.take(now), nil
+}
+
+// cleanup oublie les seaux pleins : les recréer plus tard donne le même résultat.
+// L'appelant doit détenir s.mu.
+func (s *MemoryStore) cleanup(now time.Time) {
+	for key, b := range s.buckets {
+		if b.full(now) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastCleanup = now
+}
@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrInvalidLimit est retournée quand un budget n'a pas de débit ou de rafale positifs.
+var ErrInvalidLimit = errors.New("rate limit must have a positive rate and burst")
+
+// Limit décrit un budget de type token bucket : le seau contient au plus Burst jetons
+// et se remplit de Rate jetons par seconde. Chaque requête consomme un jeton.
+type Limit struct {
+	Rate  float64 // Jetons ajoutés par seconde
+	Burst int     // Capacité du seau (rafale maximale)
+}
+
+// PerMinute construit un budget de n requêtes par minute avec une rafale de burst requêtes.
+func PerMinute(n, burst int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: burst}
+}
+
+// Window retourne la durée nécessaire pour remplir entièrement un seau vide.
+func (l Limit) Window() time.Duration {
+	return time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+}
+
+// Result est la décision prise pour une requête, avec de quoi renseigner les en-têtes RateLimit-*.
+type Result struct {
+	Allowed    bool          // La requête peut être servie
+	Limit      int           // Capacité du seau
+	Remaining  int           // Jetons restants après cette requête
+	ResetAfter time.Duration // Délai avant que le seau soit de nouveau plein
+	RetryAfter time.Duration // Délai avant le prochain jeton (nul si la requête est acceptée)
+}
+
+// Store conserve l'état des seaux. L'implémentation en mémoire (MemoryStore) suffit
+// pour une instance unique ; une implémentation partagée (cache distribué) permettrait
+// d'appliquer le même budget à plusieurs instances.
+type Store interface {
+	// Take consomme un jeton du seau identifié par key, selon le budget limit.
+	Take(key string, limit Limit, now time.Time) (Result, error)
+}
+
+// Limiter applique un budget nommé aux clients, dans un Store éventuellement partagé
+// avec d'autres budgets.
+type Limiter struct {
+	name  string
+	limit Limit
+	store Store
+}
+
+// NewLimiter crée un limiteur. Le nom préfixe les clés pour séparer les budgets d'un même store.
+func NewLimiter(name string, limit Limit, store Store) (*Limiter, error) {
+	if limit.Rate <= 0 || limit.Burst <= 0 {
+		return nil, ErrInvalidLimit
+	}
+	return &Limiter{name: name, limit: limit, store: store}, nil
+}
+
+// Name retourne le nom du budget.
+func (l *Limiter) Name() string {
+	return l.name
+}
+
+// Limit retourne le budget appliqué.
+func (l *Limiter) Limit() Limit {
+	return l.limit
+}
+
+// Allow consomme un jeton pour le client identifié par key.
+func (l *Limiter) Allow(key string) (Result, error) {
+	return l.store.Take(l.name+":"+key, l.limit, time.Now())
+}
+
+// bucket est l'état d'un seau : le nombre de jetons au dernier passage.
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+// refill ajoute les jetons accumulés depuis le dernier passage, sans dépasser la capacité.
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+		b.last = now
+	}
+}
+
+// take consomme un jeton si possible et retourne la décision correspondante.
+func (b *bucket) take(now time.Time) Result {
+	b.refill(now)
+
+	result := Result{Limit: b.limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / b.limit.Rate)
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.ResetAfter = secondsToDuration((float64(b.limit.Burst) - b.tokens) / b.limit.Rate)
+	return result
+}
+
+// full indique si le seau serait plein à l'instant now, auquel cas il peut être oublié.
+func (b *bucket) full(now time.Time) bool {
+	b.refill(now)
+	return b.tokens >= float64(b.limit.Burst)
+}
+
+// secondsToDuration convertit des secondes fractionnaires en durée.
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
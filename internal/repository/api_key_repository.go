@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository définit les méthodes d'accès aux données des clés d'API.
+type APIKeyRepository interface {
+	CreateAPIKey(key *models.APIKey) error
+	GetAPIKeyByHash(hash string) (*models.APIKey, error)
+	GetAPIKeyByID(id uint) (*models.APIKey, error)
+	ListAPIKeys() ([]models.APIKey, error)
+	RevokeAPIKey(id uint, at time.Time) error
+	TouchAPIKey(id uint, at time.Time) error
+}
+
+// GormAPIKeyRepository est l'implémentation de APIKeyRepository utilisant GORM.
+type GormAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository crée et retourne une nouvelle instance de GormAPIKeyRepository.
+func NewAPIKeyRepository(db *gorm.DB) *GormAPIKeyRepository {
+	return &GormAPIKeyRepository{db: db}
+}
+
+// CreateAPIKey insère une nouvelle clé d'API.
+func (r *GormAPIKeyRepository) CreateAPIKey(key *models.APIKey) error {
+	if err := r.db.Create(key).Error; err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+	return nil
+}
+
+// GetAPIKeyByHash récupère une clé d'API par le hash de sa valeur.
+// Il renvoie gorm.ErrRecordNotFound si aucune clé ne correspond.
+func (r *GormAPIKeyRepository) GetAPIKeyByHash(hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.Where("key_hash = ?", hash).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to get api key by hash: %w", err)
+	}
+	return &key, nil
+}
+
+// GetAPIKeyByID récupère une clé d'API par son identifiant.
+// Il renvoie gorm.ErrRecordNotFound si la clé n'existe pas.
+func (r *GormAPIKeyRepository) GetAPIKeyByID(id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.First(&key, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to get api key by id: %w", err)
+	}
+	return &key, nil
+}
+
+// ListAPIKeys récupère toutes les clés d'API, révoquées comprises, par ordre de création.
+func (r *GormAPIKeyRepository) ListAPIKeys() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	if err := r.db.Order("id").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey révoque une clé d'API. Une clé déjà révoquée conserve sa date de révocation.
+// Il renvoie gorm.ErrRecordNotFound si la clé n'existe pas.
+func (r *GormAPIKeyRepository) RevokeAPIKey(id uint, at time.Time) error {
+	key, err := r.GetAPIKeyByID(id)
+	if err != nil {
+		return err
+	}
+	if key.IsRevoked() {
+		return nil
+	}
+	if err := r.db.Model(key).Update("revoked_at", at).Error; err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return nil
+}
+
+// TouchAPIKey enregistre la date de dernière utilisation d'une clé d'API.
+func (r *GormAPIKeyRepository) TouchAPIKey(id uint, at time.Time) error {
+	result := r.db.Model(&models.APIKey{}).Where("id = ?", id).Update("last_used_at", at)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update api key last use: %w", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/cache"
+	"github.com/axellelanca/urlshortener/internal/metrics"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// LinkCacheConfig règle le cache de résolution des codes courts.
+type LinkCacheConfig struct {
+	MaxEntries  int           // Nombre maximal de codes en cache (liens et codes inconnus)
+	TTL         time.Duration // Durée de vie d'un lien en cache
+	NegativeTTL time.Duration // Durée de vie d'un code inconnu en cache
+}
+
+// CachedLinkRepository ajoute un cache LRU devant GetLinkByShortCode d'un autre LinkRepository.
+// Les codes inconnus sont aussi mis en cache (cache négatif) pour absorber les scanners.
+// Toute écriture passant par ce repository invalide le code concerné ; les modifications
+// faites hors de ce processus (autre instance, CLI) ne sont vues qu'à l'expiration de l'entrée.
+type CachedLinkRepository struct {
+	LinkRepository
+	links *cache.LRU[string, *models.Link] // nil = code inconnu
+	cfg   LinkCacheConfig
+}
+
+// NewCachedLinkRepository crée un repository qui met en cache les résolutions de repo.
+func NewCachedLinkRepository(repo LinkRepository, cfg LinkCacheConfig) *CachedLinkRepository {
+	return &CachedLinkRepository{
+		LinkRepository: repo,
+		links:          cache.New[string, *models.Link](cfg.MaxEntries),
+		cfg:            cfg,
+	}
+}
+
+// GetLinkByShortCode retourne le lien en cache s'il y est, sinon le lit et le met en cache.
+// Seule l'absence du lien est mise en cache négativement : les autres erreurs sont retournées telles quelles.
+func (r *CachedLinkRepository) GetLinkByShortCode(shortCode string) (*models.Link, error) {
+	if link, ok := r.links.Get(shortCode); ok {
+		if link == nil {
+			metrics.LinkCacheLookups.WithLabelValues("negative_hit").Inc()
+			return nil, gorm.ErrRecordNotFound
+		}
+		metrics.LinkCacheLookups.WithLabelValues("hit").Inc()
+		copied := cloneLink(*link)
+		return &copied, nil
+	}
+	metrics.LinkCacheLookups.WithLabelValues("miss").Inc()
+
+	// Une lecture en base concurrente d'une écriture ne doit pas remettre en cache
+	// une valeur déjà invalidée : on note la génération du cache avant la lecture.
+	generation := r.links.Generation()
+	link, err := r.LinkRepository.GetLinkByShortCode(shortCode)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		r.store(generation, shortCode, nil, r.cfg.NegativeTTL)
+		return nil, err
+	case err != nil:
+		return nil, err
+	}
+	// Le lien retourné à l'appelant peut être modifié : le cache garde sa propre copie.
+	copied := cloneLink(*link)
+	r.store(generation, shortCode, &copied, r.cfg.TTL)
+	return link, nil
+}
+
+// CreateLink crée le lien et oublie un éventuel code inconnu en cache.
+func (r *CachedLinkRepository) CreateLink(link *models.Link) error {
+	defer r.invalidate(link.ShortCode)
+	return r.LinkRepository.CreateLink(link)
+}
+
+// UpdateLink met à jour le lien et retire son code du cache.
+func (r *CachedLinkRepository) UpdateLink(link *models.Link, event *models.LinkAuditEvent, fields ...string) error {
+	defer r.invalidate(link.ShortCode)
+	return r.LinkRepository.UpdateLink(link, event, fields...)
+}
+
+// DeleteLink supprime le lien et retire son code du cache.
+func (r *CachedLinkRepository) DeleteLink(link *models.Link, event *models.LinkAuditEvent) error {
+	defer r.invalidate(link.ShortCode)
+	return r.LinkRepository.DeleteLink(link, event)
+}
+
+// UpdateLinkHealth met à jour l'état de santé du lien et retire son code du cache.
+func (r *CachedLinkRepository) UpdateLinkHealth(link *models.Link, event *models.LinkAuditEvent) error {
+	defer r.invalidate(link.ShortCode)
+	return r.LinkRepository.UpdateLinkHealth(link, event)
+}
+
+// MarkExpiredLinks marque les liens expirés et vide le cache si au moins un lien a changé,
+// leurs codes n'étant pas connus individuellement.
+func (r *CachedLinkRepository) MarkExpiredLinks(now time.Time) (int64, error) {
+	count, err := r.LinkRepository.MarkExpiredLinks(now)
+	if count > 0 {
+		r.links.Purge()
+	}
+	return count, err
+}
+
+// Len retourne le nombre de codes en cache.
+func (r *CachedLinkRepository) Len() int {
+	return r.links.Len()
+}
+
+// Stats retourne l'activité du cache.
+func (r *CachedLinkRepository) Stats() cache.Stats {
+	return r.links.Stats()
+}
+
+// invalidate retire un code du cache.
+func (r *CachedLinkRepository) invalidate(shortCode string) {
+	r.links.Remove(shortCode)
+}
+
+// store met un lien (ou nil pour un code inconnu) en cache, sauf si une invalidation a eu lieu
+// depuis generation, et comptabilise l'éviction éventuelle.
+func (r *CachedLinkRepository) store(generation uint64, shortCode string, link *models.Link, ttl time.Duration) {
+	if _, evicted := r.links.SetIfGeneration(shortCode, link, ttl, generation); evicted {
+		metrics.LinkCacheEvictions.Inc()
+	}
+}
@@ -1,10 +1,17 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/axellelanca/urlshortener/internal/hll"
 	"github.com/axellelanca/urlshortener/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ClickRepository est une interface qui définit les méthodes d'accès aux données
@@ -13,11 +20,45 @@ import (
 type ClickRepository interface {
 	CreateClick(click *models.Click) error
 	CountClicksByLinkID(linkID uint) (int, error) // Utilisé par LinkService pour les stats
+	CountClicksBySlot(linkID uint, from, to, origin time.Time, slot time.Duration) ([]SlotCount, error)
+	GetClickBreakdown(linkID uint, dimension string, limit int) ([]BreakdownEntry, error)
+	CreateClickBatch(clicks []models.Click, visitors map[uint]*hll.Sketch) error
+	GetVisitorSketch(linkID uint) (*hll.Sketch, error)
+}
+
+// Dimensions disponibles pour les répartitions de clics, associées à leur colonne.
+const (
+	DimensionReferrer = "referrer"
+	DimensionBrowser  = "browser"
+	DimensionOS       = "os"
+	DimensionDevice   = "device"
+)
+
+// breakdownColumns fait correspondre chaque dimension à sa colonne dans la table 'clicks'.
+// Seules ces colonnes peuvent être utilisées dans le GROUP BY.
+var breakdownColumns = map[string]string{
+	DimensionReferrer: "referrer_host",
+	DimensionBrowser:  "browser",
+	DimensionOS:       "os",
+	DimensionDevice:   "device_type",
+}
+
+// BreakdownEntry représente le nombre de clics pour une valeur d'une dimension.
+type BreakdownEntry struct {
+	Value  string `json:"value"`
+	Clicks int    `json:"clicks"`
+}
+
+// SlotCount est le nombre de clics d'une tranche de temps, numérotée à partir de l'origine demandée.
+type SlotCount struct {
+	Slot   int64
+	Clicks int
 }
 
 // GormClickRepository est l'implémentation de l'interface ClickRepository utilisant GORM.
 type GormClickRepository struct {
-	db *gorm.DB // Référence à l'instance de la base de données GORM
+	db       *gorm.DB   // Référence à l'instance de la base de données GORM
+	sketchMu sync.Mutex // Sérialise les lots des workers d'un même processus sous SQLite
 }
 
 // NewClickRepository crée et retourne une nouvelle instance de GormClickRepository.
@@ -49,4 +90,148 @@ func (r *GormClickRepository) CountClicksByLinkID(linkID uint) (int, error) {
 	}
 
 	return int(count), nil // Convert the int64 count to an int
-}
\ No newline at end of file
+}
+
+// CountClicksBySlot compte les clics d'un lien dans l'intervalle [from, to[, regroupés en tranches
+// de durée slot numérotées à partir de origin (qui doit précéder from). Le regroupement est fait
+// par la base ; seules les tranches contenant au moins un clic sont retournées, par ordre croissant.
+func (r *GormClickRepository) CountClicksBySlot(linkID uint, from, to, origin time.Time, slot time.Duration) ([]SlotCount, error) {
+	slotExpr := fmt.Sprintf("(%s - ?) / ?", r.epochSeconds("timestamp"))
+	if r.db.Dialector.Name() == "mysql" {
+		// '/' donne un décimal sous MySQL : DIV effectue la division entière.
+		slotExpr = fmt.Sprintf("(%s - ?) DIV ?", r.epochSeconds("timestamp"))
+	}
+
+	var counts []SlotCount
+	result := r.db.Model(&models.Click{}).
+		Select(slotExpr+" AS slot, COUNT(*) AS clicks", origin.Unix(), int64(slot/time.Second)).
+		Where("link_id = ? AND timestamp >= ? AND timestamp < ?", linkID, from.UTC(), to.UTC()).
+		Group("slot").
+		Order("slot").
+		Scan(&counts)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to count clicks by slot for link ID %d: %w", linkID, result.Error)
+	}
+	return counts, nil
+}
+
+// epochSeconds retourne l'expression SQL convertissant une colonne horodatée en secondes
+// depuis l'epoch Unix, arrondies à la seconde inférieure, selon le moteur.
+func (r *GormClickRepository) epochSeconds(column string) string {
+	switch r.db.Dialector.Name() {
+	case "postgres":
+		return fmt.Sprintf("CAST(FLOOR(EXTRACT(EPOCH FROM %s)) AS BIGINT)", column)
+	case "mysql":
+		// Les dates sont enregistrées en UTC (loc=UTC) : pas de conversion par le fuseau de la session.
+		return fmt.Sprintf("TIMESTAMPDIFF(SECOND, '1970-01-01 00:00:00', %s)", column)
+	default:
+		return fmt.Sprintf("CAST(strftime('%%s', %s) AS INTEGER)", column)
+	}
+}
+
+// GetClickBreakdown retourne les valeurs les plus fréquentes d'une dimension pour un lien,
+// triées par nombre de clics décroissant.
+func (r *GormClickRepository) GetClickBreakdown(linkID uint, dimension string, limit int) ([]BreakdownEntry, error) {
+	column, ok := breakdownColumns[dimension]
+	if !ok {
+		return nil, fmt.Errorf("unknown breakdown dimension %q", dimension)
+	}
+
+	var entries []BreakdownEntry
+	result := r.db.Model(&models.Click{}).
+		Select(column+" AS value, COUNT(*) AS clicks").
+		Where("link_id = ?", linkID).
+		Group(column).
+		Order("clicks DESC").Order(column).
+		Limit(limit).
+		Scan(&entries)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get %s breakdown for link ID %d: %w", dimension, linkID, result.Error)
+	}
+	return entries, nil
+}
+
+// clickInsertBatchSize borne le nombre de lignes par requête INSERT pour rester
+// sous la limite de paramètres des moteurs SQL.
+const clickInsertBatchSize = 200
+
+// CreateClickBatch insère un lot de clics (insertion multi-lignes) et fusionne les sketches
+// de visiteurs associés, par lien, dans une seule transaction : soit tout le lot est
+// persisté, soit rien ne l'est et l'appelant peut réessayer.
+func (r *GormClickRepository) CreateClickBatch(clicks []models.Click, visitors map[uint]*hll.Sketch) error {
+	// PostgreSQL et MySQL protègent chaque fusion de sketch par un verrou de ligne.
+	// SQLite n'a qu'un verrou d'écriture global, sans SELECT ... FOR UPDATE : on y sérialise
+	// les lots des workers pour éviter les conflits d'écriture.
+	if r.db.Dialector.Name() == "sqlite" {
+		r.sketchMu.Lock()
+		defer r.sketchMu.Unlock()
+	}
+
+	// Les lignes sont verrouillées dans l'ordre des IDs de lien, pour que deux lots
+	// concurrents ne puissent pas s'attendre mutuellement (deadlock).
+	linkIDs := slices.Sorted(maps.Keys(visitors))
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if len(clicks) > 0 {
+			if err := tx.CreateInBatches(clicks, clickInsertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		for _, linkID := range linkIDs {
+			if err := mergeVisitorSketch(tx, linkID, visitors[linkID]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create click batch of %d click(s): %w", len(clicks), err)
+	}
+	return nil
+}
+
+// mergeVisitorSketch fusionne un sketch de visiteurs dans le sketch persisté du lien.
+// Une ligne vide est d'abord créée si besoin (ON CONFLICT DO NOTHING) afin que le
+// SELECT ... FOR UPDATE (ignoré par SQLite) ait toujours une ligne à verrouiller : plusieurs
+// instances partageant la base ne perdent ainsi pas leurs fusions respectives, même au
+// premier visiteur d'un lien.
+func mergeVisitorSketch(tx *gorm.DB, linkID uint, sketch *hll.Sketch) error {
+	empty := models.VisitorSketch{LinkID: linkID, Registers: hll.New().Bytes()}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&empty).Error; err != nil {
+		return err
+	}
+
+	var stored models.VisitorSketch
+	err := tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("link_id = ?", linkID).First(&stored).Error
+	if err != nil {
+		return err
+	}
+
+	merged, err := hll.FromBytes(stored.Registers)
+	if err != nil {
+		return err
+	}
+	merged.Merge(sketch)
+
+	return tx.Model(&stored).Update("registers", merged.Bytes()).Error
+}
+
+// GetVisitorSketch récupère le sketch des visiteurs d'un lien.
+// Un lien sans visiteur enregistré retourne un sketch vide.
+func (r *GormClickRepository) GetVisitorSketch(linkID uint) (*hll.Sketch, error) {
+	var stored models.VisitorSketch
+	err := r.db.Where("link_id = ?", linkID).First(&stored).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return hll.New(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get visitor sketch for link ID %d: %w", linkID, err)
+	}
+
+	sketch, err := hll.FromBytes(stored.Registers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode visitor sketch for link ID %d: %w", linkID, err)
+	}
+	return sketch, nil
+}
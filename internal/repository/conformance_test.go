@@ -0,0 +1,641 @@
+package repository
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/hll"
+	"github.com/axellelanca/urlshortener/internal/migrations"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// repositories regroupe une implémentation de chaque repository sur un même stockage vide.
+type repositories struct {
+	links      LinkRepository
+	clicks     ClickRepository
+	apiKeys    APIKeyRepository
+	linkChecks LinkCheckRepository
+	linkAudit  LinkAuditRepository
+}
+
+// newGormRepositories crée les repositories GORM sur une base SQLite temporaire migrée.
+func newGormRepositories(t *testing.T) repositories {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "conformance.db")
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{
+		Logger:         logger.Discard,
+		TranslateError: true, // Comme la fabrique de connexions du binaire
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("get sql database: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if _, err := migrations.NewMigrator(db).Up(); err != nil {
+		t.Fatalf("migrate database: %v", err)
+	}
+	return repositories{
+		links:      NewLinkRepository(db),
+		clicks:     NewClickRepository(db),
+		apiKeys:    NewAPIKeyRepository(db),
+		linkChecks: NewLinkCheckRepository(db),
+		linkAudit:  NewLinkAuditRepository(db),
+	}
+}
+
+// newMemoryRepositories crée les repositories en mémoire sur un stockage vide.
+func newMemoryRepositories(t *testing.T) repositories {
+	store := NewMemoryStore()
+	return repositories{
+		links:      NewMemoryLinkRepository(store),
+		clicks:     NewMemoryClickRepository(store),
+		apiKeys:    NewMemoryAPIKeyRepository(store),
+		linkChecks: NewMemoryLinkCheckRepository(store),
+		linkAudit:  NewMemoryLinkAuditRepository(store),
+	}
+}
+
+func TestGormRepositoriesConformance(t *testing.T) {
+	runConformance(t, newGormRepositories)
+}
+
+func TestMemoryRepositoriesConformance(t *testing.T) {
+	runConformance(t, newMemoryRepositories)
+}
+
+// runConformance exécute la suite commune : chaque cas reçoit un stockage vide.
+func runConformance(t *testing.T, newRepositories func(t *testing.T) repositories) {
+	cases := []struct {
+		name string
+		run  func(t *testing.T, r repositories)
+	}{
+		{"LinkCreateAndGet", testLinkCreateAndGet},
+		{"LinkDuplicateShortCode", testLinkDuplicateShortCode},
+		{"LinkUpdate", testLinkUpdate},
+		{"LinkSoftDelete", testLinkSoftDelete},
+		{"LinkActiveLinks", testLinkActiveLinks},
+		{"LinkListFilters", testLinkListFilters},
+		{"LinkListPagination", testLinkListPagination},
+		{"LinkMarkExpired", testLinkMarkExpired},
+		{"LinkUpdateHealth", testLinkUpdateHealth},
+		{"LinkReserveClick", testLinkReserveClick},
+		{"ClickCountAndSlots", testClickCountAndSlots},
+		{"ClickBreakdown", testClickBreakdown},
+		{"ClickBatchAndVisitors", testClickBatchAndVisitors},
+		{"APIKeyLifecycle", testAPIKeyLifecycle},
+		{"LinkChecks", testLinkChecks},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.run(t, newRepositories(t))
+		})
+	}
+}
+
+// mustCreateLink crée un lien et échoue le test en cas d'erreur.
+func mustCreateLink(t *testing.T, r repositories, link models.Link) *models.Link {
+	t.Helper()
+	if err := r.links.CreateLink(&link); err != nil {
+		t.Fatalf("create link %q: %v", link.ShortCode, err)
+	}
+	return &link
+}
+
+// mustCreateClicks enregistre n clics pour un lien.
+func mustCreateClicks(t *testing.T, r repositories, linkID uint, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := r.clicks.CreateClick(&models.Click{LinkID: linkID, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("create click: %v", err)
+		}
+	}
+}
+
+// shortCodes retourne les codes courts d'une page de liens.
+func shortCodes(summaries []LinkSummary) []string {
+	codes := make([]string, 0, len(summaries))
+	for _, summary := range summaries {
+		codes = append(codes, summary.ShortCode)
+	}
+	return codes
+}
+
+// assertCodes vérifie la liste ordonnée des codes courts obtenus.
+func assertCodes(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got codes %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("got codes %v, want %v", got, want)
+		}
+	}
+}
+
+func testLinkCreateAndGet(t *testing.T, r repositories) {
+	expiresAt := time.Now().Add(time.Hour).UTC()
+	created := mustCreateLink(t, r, models.Link{ShortCode: "abc123", LongURL: "https://example.com", ExpiresAt: &expiresAt, MaxClicks: 5})
+	if created.ID == 0 || created.CreatedAt.IsZero() {
+		t.Fatalf("create link did not set ID and CreatedAt: %+v", created)
+	}
+
+	got, err := r.links.GetLinkByShortCode("abc123")
+	if err != nil {
+		t.Fatalf("get link: %v", err)
+	}
+	if got.ID != created.ID || got.LongURL != "https://example.com" || got.MaxClicks != 5 ||
+		got.ExpiresAt == nil || !got.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("got %+v, want %+v", got, created)
+	}
+
+	if _, err := r.links.GetLinkByShortCode("missing"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("get missing link: got %v, want gorm.ErrRecordNotFound", err)
+	}
+}
+
+func testLinkDuplicateShortCode(t *testing.T, r repositories) {
+	mustCreateLink(t, r, models.Link{ShortCode: "dup", LongURL: "https://a.example"})
+	err := r.links.CreateLink(&models.Link{ShortCode: "dup", LongURL: "https://b.example"})
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("create duplicate: got %v, want gorm.ErrDuplicatedKey", err)
+	}
+}
+
+func testLinkUpdate(t *testing.T, r repositories) {
+	past := time.Now().Add(-time.Minute)
+	link := mustCreateLink(t, r, models.Link{ShortCode: "upd", LongURL: "https://old.example", ExpiresAt: &past})
+
+	// Le sweeper et le moniteur modifient le lien après sa lecture.
+	stale := *link
+	if _, err := r.links.MarkExpiredLinks(time.Now()); err != nil {
+		t.Fatalf("mark expired links: %v", err)
+	}
+	if err := r.links.UpdateLinkHealth(&models.Link{ID: link.ID, HealthAction: models.HealthActionDisable}, nil); err != nil {
+		t.Fatalf("update link health: %v", err)
+	}
+
+	stale.LongURL = "https://new.example"
+	stale.Disabled = true
+	stale.FallbackURL = "https://ignored.example" // Non nommé : ne doit pas être écrit
+	event := &models.LinkAuditEvent{LinkID: link.ID, Actor: models.AuditActorAPI, Action: models.AuditLinkUpdated}
+	if err := r.links.UpdateLink(&stale, event, "LongURL", "Disabled"); err != nil {
+		t.Fatalf("update link: %v", err)
+	}
+	if err := r.links.UpdateLink(&stale, nil); !errors.Is(err, ErrNoFieldToUpdate) {
+		t.Fatalf("update without fields: got %v, want ErrNoFieldToUpdate", err)
+	}
+
+	got, err := r.links.GetLinkByShortCode("upd")
+	if err != nil {
+		t.Fatalf("get link: %v", err)
+	}
+	if got.LongURL != "https://new.example" || !got.Disabled || got.FallbackURL != "" {
+		t.Fatalf("update not persisted as requested: %+v", got)
+	}
+	if !got.Expired || got.HealthAction != models.HealthActionDisable {
+		t.Fatalf("stale update overwrote the expiry or the health action: %+v", got)
+	}
+
+	events, err := r.linkAudit.GetAuditEvents(link.ID, 10)
+	if err != nil || len(events) != 1 || events[0].ID != event.ID || events[0].Action != models.AuditLinkUpdated {
+		t.Fatalf("update audit events: %+v, %v", events, err)
+	}
+}
+
+func testLinkSoftDelete(t *testing.T, r repositories) {
+	link := mustCreateLink(t, r, models.Link{ShortCode: "del", LongURL: "https://example.com"})
+	mustCreateLink(t, r, models.Link{ShortCode: "keep", LongURL: "https://example.com"})
+	event := &models.LinkAuditEvent{LinkID: link.ID, Actor: models.AuditActorCLI, Action: models.AuditLinkDeleted}
+	if err := r.links.DeleteLink(link, event); err != nil {
+		t.Fatalf("delete link: %v", err)
+	}
+	if events, err := r.linkAudit.GetAuditEvents(link.ID, 10); err != nil || len(events) != 1 || events[0].Actor != models.AuditActorCLI {
+		t.Fatalf("delete audit events: %+v, %v", events, err)
+	}
+
+	if _, err := r.links.GetLinkByShortCode("del"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("get deleted link: got %v, want gorm.ErrRecordNotFound", err)
+	}
+	exists, err := r.links.ShortCodeExists("del")
+	if err != nil || !exists {
+		t.Fatalf("deleted short code must stay reserved: exists=%v err=%v", exists, err)
+	}
+	all, err := r.links.GetAllLinks()
+	if err != nil || len(all) != 1 || all[0].ShortCode != "keep" {
+		t.Fatalf("get all links: %+v, %v", all, err)
+	}
+}
+
+func testLinkActiveLinks(t *testing.T, r repositories) {
+	mustCreateLink(t, r, models.Link{ShortCode: "active", LongURL: "https://example.com"})
+	mustCreateLink(t, r, models.Link{ShortCode: "disabled", LongURL: "https://example.com", Disabled: true})
+	mustCreateLink(t, r, models.Link{ShortCode: "expired", LongURL: "https://example.com", Expired: true})
+
+	links, err := r.links.GetActiveLinks()
+	if err != nil || len(links) != 1 || links[0].ShortCode != "active" {
+		t.Fatalf("get active links: %+v, %v", links, err)
+	}
+}
+
+func testLinkListFilters(t *testing.T, r repositories) {
+	past := time.Now().Add(-time.Hour)
+	mustCreateLink(t, r, models.Link{ShortCode: "root", LongURL: "https://Example.com"})
+	mustCreateLink(t, r, models.Link{ShortCode: "sub", LongURL: "https://www.example.com/path"})
+	mustCreateLink(t, r, models.Link{ShortCode: "port", LongURL: "http://example.com:8080?q=1"})
+	mustCreateLink(t, r, models.Link{ShortCode: "other", LongURL: "https://notexample.com/"})
+	mustCreateLink(t, r, models.Link{ShortCode: "off", LongURL: "https://other.org", Disabled: true})
+	mustCreateLink(t, r, models.Link{ShortCode: "old", LongURL: "https://other.org", ExpiresAt: &past})
+
+	list := func(filter LinkFilter) []string {
+		t.Helper()
+		filter.Limit = 10
+		summaries, err := r.links.ListLinks(filter)
+		if err != nil {
+			t.Fatalf("list links %+v: %v", filter, err)
+		}
+		return shortCodes(summaries)
+	}
+
+	assertCodes(t, list(LinkFilter{Domain: "example.com"}), "root", "sub", "port")
+	// Les jokers de LIKE sont comparés littéralement.
+	assertCodes(t, list(LinkFilter{Domain: "exa_ple.com"}))
+	assertCodes(t, list(LinkFilter{Domain: "%"}))
+	assertCodes(t, list(LinkFilter{Domain: "example%"}))
+	assertCodes(t, list(LinkFilter{Status: StatusDisabled}), "off")
+	assertCodes(t, list(LinkFilter{Status: StatusExpired}), "old")
+	assertCodes(t, list(LinkFilter{Status: StatusActive}), "root", "sub", "port", "other")
+
+	future := time.Now().Add(time.Hour)
+	assertCodes(t, list(LinkFilter{CreatedFrom: &future}))
+	assertCodes(t, list(LinkFilter{CreatedTo: &future, Domain: "other.org"}), "off", "old")
+}
+
+func testLinkListPagination(t *testing.T, r repositories) {
+	a := mustCreateLink(t, r, models.Link{ShortCode: "aaa", LongURL: "https://example.com"})
+	b := mustCreateLink(t, r, models.Link{ShortCode: "bbb", LongURL: "https://example.com"})
+	c := mustCreateLink(t, r, models.Link{ShortCode: "ccc", LongURL: "https://example.com"})
+	d := mustCreateLink(t, r, models.Link{ShortCode: "ddd", LongURL: "https://example.com"})
+	mustCreateClicks(t, r, a.ID, 2)
+	mustCreateClicks(t, r, b.ID, 5)
+	mustCreateClicks(t, r, c.ID, 2)
+	mustCreateClicks(t, r, d.ID, 0)
+
+	page := func(filter LinkFilter) []LinkSummary {
+		t.Helper()
+		summaries, err := r.links.ListLinks(filter)
+		if err != nil {
+			t.Fatalf("list links %+v: %v", filter, err)
+		}
+		return summaries
+	}
+
+	first := page(LinkFilter{SortBy: SortByClicks, Descending: true, Limit: 2})
+	assertCodes(t, shortCodes(first), "bbb", "ccc")
+	if first[0].ClickCount != 5 || first[1].ClickCount != 2 {
+		t.Fatalf("click counts: got %d and %d", first[0].ClickCount, first[1].ClickCount)
+	}
+	last := first[len(first)-1]
+	second := page(LinkFilter{SortBy: SortByClicks, Descending: true, Limit: 2, AfterID: last.ID, AfterClicks: last.ClickCount})
+	assertCodes(t, shortCodes(second), "aaa", "ddd")
+
+	ascending := page(LinkFilter{SortBy: SortByClicks, Limit: 10})
+	assertCodes(t, shortCodes(ascending), "ddd", "aaa", "ccc", "bbb")
+
+	assertCodes(t, shortCodes(page(LinkFilter{SortBy: SortByCreatedAt, Limit: 2, AfterID: b.ID})), "ccc", "ddd")
+	assertCodes(t, shortCodes(page(LinkFilter{SortBy: SortByCreatedAt, Descending: true, Limit: 10, AfterID: c.ID})), "bbb", "aaa")
+}
+
+func testLinkMarkExpired(t *testing.T, r repositories) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+	mustCreateLink(t, r, models.Link{ShortCode: "past", LongURL: "https://example.com", ExpiresAt: &past})
+	mustCreateLink(t, r, models.Link{ShortCode: "future", LongURL: "https://example.com", ExpiresAt: &future})
+	budget := mustCreateLink(t, r, models.Link{ShortCode: "budget", LongURL: "https://example.com", MaxClicks: 2})
+	// Le budget est épuisé par les réservations, même si les clics ne sont pas encore enregistrés.
+	for i := 0; i < 2; i++ {
+		if _, err := r.links.ReserveClick(budget.ID); err != nil {
+			t.Fatalf("reserve click: %v", err)
+		}
+	}
+
+	marked, err := r.links.MarkExpiredLinks(now)
+	if err != nil || marked != 2 {
+		t.Fatalf("mark expired links: got %d, %v, want 2", marked, err)
+	}
+	if marked, err := r.links.MarkExpiredLinks(now); err != nil || marked != 0 {
+		t.Fatalf("mark expired links again: got %d, %v, want 0", marked, err)
+	}
+
+	for code, expired := range map[string]bool{"past": true, "future": false, "budget": true} {
+		link, err := r.links.GetLinkByShortCode(code)
+		if err != nil || link.Expired != expired {
+			t.Fatalf("link %q: expired=%v err=%v, want expired=%v", code, link != nil && link.Expired, err, expired)
+		}
+	}
+}
+
+func testLinkUpdateHealth(t *testing.T, r repositories) {
+	link := mustCreateLink(t, r, models.Link{ShortCode: "sick", LongURL: "https://example.com", MaxClicks: 3})
+	downSince := time.Now().Add(-2 * time.Hour).UTC()
+	link.DownSince = &downSince
+	link.HealthAction = models.HealthActionDisable
+	link.MaxClicks = 99 // Ne doit pas être enregistré
+
+	event := &models.LinkAuditEvent{LinkID: link.ID, Actor: models.AuditActorMonitor, Action: models.AuditLinkAutoDisabled, Detail: "down"}
+	if err := r.links.UpdateLinkHealth(link, event); err != nil {
+		t.Fatalf("update link health: %v", err)
+	}
+	if event.ID == 0 {
+		t.Fatalf("audit event ID not set")
+	}
+	if err := r.links.UpdateLinkHealth(&models.Link{ID: link.ID}, &models.LinkAuditEvent{
+		LinkID: link.ID, Actor: models.AuditActorMonitor, Action: models.AuditLinkReinstated,
+	}); err != nil {
+		t.Fatalf("reinstate link health: %v", err)
+	}
+
+	got, err := r.links.GetLinkByShortCode("sick")
+	if err != nil {
+		t.Fatalf("get link: %v", err)
+	}
+	if got.DownSince != nil || got.HealthAction != "" || got.MaxClicks != 3 {
+		t.Fatalf("unexpected link after health updates: %+v", got)
+	}
+
+	events, err := r.linkAudit.GetAuditEvents(link.ID, 10)
+	if err != nil || len(events) != 2 {
+		t.Fatalf("get audit events: %+v, %v", events, err)
+	}
+	if events[0].Action != models.AuditLinkReinstated || events[1].Action != models.AuditLinkAutoDisabled {
+		t.Fatalf("audit events not ordered from newest: %+v", events)
+	}
+	if limited, err := r.linkAudit.GetAuditEvents(link.ID, 1); err != nil || len(limited) != 1 {
+		t.Fatalf("get limited audit events: %+v, %v", limited, err)
+	}
+}
+
+func testLinkReserveClick(t *testing.T, r repositories) {
+	link := mustCreateLink(t, r, models.Link{ShortCode: "budget", LongURL: "https://example.com", MaxClicks: 3})
+	unlimited := mustCreateLink(t, r, models.Link{ShortCode: "unlimited", LongURL: "https://example.com"})
+
+	for i := 1; i <= 4; i++ {
+		reserved, err := r.links.ReserveClick(link.ID)
+		if err != nil {
+			t.Fatalf("reserve click %d: %v", i, err)
+		}
+		if want := i <= 3; reserved != want {
+			t.Fatalf("reserve click %d: got %v, want %v", i, reserved, want)
+		}
+	}
+	if reserved, err := r.links.ReserveClick(unlimited.ID); err != nil || reserved {
+		t.Fatalf("reserve click without budget: got %v, %v, want false", reserved, err)
+	}
+
+	// Une mise à jour faite avec une copie périmée du lien ne remet pas le compteur à zéro.
+	link.LongURL = "https://new.example"
+	if err := r.links.UpdateLink(link, nil, "LongURL"); err != nil {
+		t.Fatalf("update link: %v", err)
+	}
+	got, err := r.links.GetLinkByShortCode("budget")
+	if err != nil {
+		t.Fatalf("get link: %v", err)
+	}
+	if got.ReservedClicks != 3 || got.LongURL != "https://new.example" {
+		t.Fatalf("got reserved clicks %d and url %q, want 3 and the new url", got.ReservedClicks, got.LongURL)
+	}
+}
+
+func testClickCountAndSlots(t *testing.T, r repositories) {
+	link := mustCreateLink(t, r, models.Link{ShortCode: "clk", LongURL: "https://example.com"})
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	offsets := []time.Duration{2 * time.Hour, 0, time.Hour - 100*time.Millisecond, time.Hour, 5 * time.Hour}
+	for _, offset := range offsets {
+		if err := r.clicks.CreateClick(&models.Click{LinkID: link.ID, Timestamp: base.Add(offset)}); err != nil {
+			t.Fatalf("create click: %v", err)
+		}
+	}
+
+	if count, err := r.clicks.CountClicksByLinkID(link.ID); err != nil || count != 5 {
+		t.Fatalf("click repository count: got %d, %v, want 5", count, err)
+	}
+	if count, err := r.links.CountClicksByLinkID(link.ID); err != nil || count != 5 {
+		t.Fatalf("link repository count: got %d, %v, want 5", count, err)
+	}
+
+	// Tranches d'une heure depuis base - 30 min : le clic à 59,9 min reste dans la tranche de base.
+	origin := base.Add(-30 * time.Minute)
+	counts, err := r.clicks.CountClicksBySlot(link.ID, base, base.Add(5*time.Hour), origin, time.Hour)
+	if err != nil {
+		t.Fatalf("count clicks by slot: %v", err)
+	}
+	want := []SlotCount{{Slot: 0, Clicks: 1}, {Slot: 1, Clicks: 2}, {Slot: 2, Clicks: 1}}
+	if len(counts) != len(want) {
+		t.Fatalf("got slots %v, want %v", counts, want)
+	}
+	for i := range want {
+		if counts[i] != want[i] {
+			t.Fatalf("got slots %v, want %v", counts, want)
+		}
+	}
+}
+
+func testClickBreakdown(t *testing.T, r repositories) {
+	link := mustCreateLink(t, r, models.Link{ShortCode: "brk", LongURL: "https://example.com"})
+	for _, browser := range []string{"Firefox", "Chrome", "Firefox", "Safari", "Chrome", "Firefox"} {
+		if err := r.clicks.CreateClick(&models.Click{LinkID: link.ID, Timestamp: time.Now(), Browser: browser}); err != nil {
+			t.Fatalf("create click: %v", err)
+		}
+	}
+
+	entries, err := r.clicks.GetClickBreakdown(link.ID, DimensionBrowser, 2)
+	if err != nil {
+		t.Fatalf("get breakdown: %v", err)
+	}
+	want := []BreakdownEntry{{Value: "Firefox", Clicks: 3}, {Value: "Chrome", Clicks: 2}}
+	if len(entries) != len(want) || entries[0] != want[0] || entries[1] != want[1] {
+		t.Fatalf("got breakdown %+v, want %+v", entries, want)
+	}
+
+	if _, err := r.clicks.GetClickBreakdown(link.ID, "country", 5); err == nil {
+		t.Fatalf("unknown dimension must fail")
+	}
+}
+
+func testClickBatchAndVisitors(t *testing.T, r repositories) {
+	link := mustCreateLink(t, r, models.Link{ShortCode: "bat", LongURL: "https://example.com"})
+
+	empty, err := r.clicks.GetVisitorSketch(link.ID)
+	if err != nil || empty.Count() != 0 {
+		t.Fatalf("sketch of a link without visitors: %v, %v", empty, err)
+	}
+
+	for batch := 0; batch < 2; batch++ {
+		sketch := hll.New()
+		clicks := make([]models.Click, 3)
+		for i := range clicks {
+			clicks[i] = models.Click{LinkID: link.ID, Timestamp: time.Now()}
+			sketch.Add(uint64(batch*3+i+1) * 0x9E3779B97F4A7C15)
+		}
+		if err := r.clicks.CreateClickBatch(clicks, map[uint]*hll.Sketch{link.ID: sketch}); err != nil {
+			t.Fatalf("create click batch: %v", err)
+		}
+		if clicks[0].ID == 0 {
+			t.Fatalf("click batch did not set IDs")
+		}
+	}
+
+	if count, err := r.clicks.CountClicksByLinkID(link.ID); err != nil || count != 6 {
+		t.Fatalf("count clicks: got %d, %v, want 6", count, err)
+	}
+	sketch, err := r.clicks.GetVisitorSketch(link.ID)
+	if err != nil {
+		t.Fatalf("get visitor sketch: %v", err)
+	}
+	if visitors := sketch.Count(); visitors < 5 || visitors > 7 {
+		t.Fatalf("merged sketch estimates %d visitors, want about 6", visitors)
+	}
+}
+
+func testAPIKeyLifecycle(t *testing.T, r repositories) {
+	first := &models.APIKey{Name: "first", Prefix: "usk_a", KeyHash: "hash-a", Scopes: models.ScopeLinksRead}
+	second := &models.APIKey{Name: "second", Prefix: "usk_b", KeyHash: "hash-b", Scopes: models.ScopeStatsRead}
+	for _, key := range []*models.APIKey{first, second} {
+		if err := r.apiKeys.CreateAPIKey(key); err != nil {
+			t.Fatalf("create api key: %v", err)
+		}
+	}
+	if err := r.apiKeys.CreateAPIKey(&models.APIKey{Name: "dup", Prefix: "usk_c", KeyHash: "hash-a", Scopes: "x"}); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("create duplicate api key: got %v, want gorm.ErrDuplicatedKey", err)
+	}
+
+	got, err := r.apiKeys.GetAPIKeyByHash("hash-b")
+	if err != nil || got.ID != second.ID || got.Name != "second" {
+		t.Fatalf("get api key by hash: %+v, %v", got, err)
+	}
+	if _, err := r.apiKeys.GetAPIKeyByHash("nope"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("get missing api key: got %v, want gorm.ErrRecordNotFound", err)
+	}
+	if _, err := r.apiKeys.GetAPIKeyByID(999); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("get missing api key by id: got %v, want gorm.ErrRecordNotFound", err)
+	}
+
+	revokedAt := time.Now().Add(-time.Minute).UTC()
+	if err := r.apiKeys.RevokeAPIKey(first.ID, revokedAt); err != nil {
+		t.Fatalf("revoke api key: %v", err)
+	}
+	if err := r.apiKeys.RevokeAPIKey(first.ID, time.Now()); err != nil {
+		t.Fatalf("revoke api key again: %v", err)
+	}
+	if err := r.apiKeys.RevokeAPIKey(999, time.Now()); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("revoke missing api key: got %v, want gorm.ErrRecordNotFound", err)
+	}
+	usedAt := time.Now().UTC()
+	if err := r.apiKeys.TouchAPIKey(second.ID, usedAt); err != nil {
+		t.Fatalf("touch api key: %v", err)
+	}
+
+	keys, err := r.apiKeys.ListAPIKeys()
+	if err != nil || len(keys) != 2 || keys[0].Name != "first" || keys[1].Name != "second" {
+		t.Fatalf("list api keys: %+v, %v", keys, err)
+	}
+	if keys[0].RevokedAt == nil || !keys[0].RevokedAt.Equal(revokedAt) {
+		t.Fatalf("revocation date must be kept: %v, want %v", keys[0].RevokedAt, revokedAt)
+	}
+	if keys[1].LastUsedAt == nil || !keys[1].LastUsedAt.Equal(usedAt) {
+		t.Fatalf("last use date: %v, want %v", keys[1].LastUsedAt, usedAt)
+	}
+}
+
+func testLinkChecks(t *testing.T, r repositories) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	checks := []models.LinkCheck{
+		{LinkID: 1, CheckedAt: base, Up: true, State: models.LinkStateUp},
+		{LinkID: 1, CheckedAt: base.Add(time.Hour), Up: false, State: models.LinkStateUp, ConsecutiveFailures: 1,
+			ErrorClass: models.CheckErrorHTTP, RedirectChain: []models.RedirectHop{{URL: "https://example.com/a", StatusCode: 301}}},
+		{LinkID: 2, CheckedAt: base.Add(30 * time.Minute), Up: true, State: models.LinkStateUp},
+		{LinkID: 1, CheckedAt: base.Add(2 * time.Hour), Up: true, State: models.LinkStateUp},
+	}
+	for i := range checks {
+		if err := r.linkChecks.CreateLinkCheck(&checks[i]); err != nil {
+			t.Fatalf("create link check: %v", err)
+		}
+	}
+
+	latest, err := r.linkChecks.GetLatestChecks()
+	if err != nil || len(latest) != 2 || latest[1].ID != checks[3].ID || latest[2].ID != checks[2].ID {
+		t.Fatalf("get latest checks: %+v, %v", latest, err)
+	}
+
+	recent, err := r.linkChecks.GetRecentChecks(1, 2)
+	if err != nil || len(recent) != 2 || recent[0].ID != checks[3].ID || recent[1].ID != checks[1].ID {
+		t.Fatalf("get recent checks: %+v, %v", recent, err)
+	}
+	if chain := recent[1].RedirectChain; len(chain) != 1 || chain[0].StatusCode != 301 {
+		t.Fatalf("redirect chain not persisted: %+v", chain)
+	}
+
+	total, up, err := r.linkChecks.CountChecksSince(1, base.Add(time.Hour))
+	if err != nil || total != 2 || up != 1 {
+		t.Fatalf("count checks since: total=%d up=%d err=%v, want 2 and 1", total, up, err)
+	}
+
+	deleted, err := r.linkChecks.DeleteChecksBefore(base.Add(time.Hour))
+	if err != nil || deleted != 2 {
+		t.Fatalf("delete checks before: got %d, %v, want 2", deleted, err)
+	}
+	if total, _, err := r.linkChecks.CountChecksSince(1, base); err != nil || total != 2 {
+		t.Fatalf("remaining checks: got %d, %v, want 2", total, err)
+	}
+}
+
+// TestMemoryRepositoriesConcurrentAccess vérifie que le stockage en mémoire supporte
+// les écritures des workers de clics concurrentes aux lectures de l'API (à lancer avec -race).
+func TestMemoryRepositoriesConcurrentAccess(t *testing.T) {
+	r := newMemoryRepositories(t)
+	link := mustCreateLink(t, r, models.Link{ShortCode: "hot", LongURL: "https://example.com"})
+
+	const writers, clicksPerWriter = 8, 50
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < clicksPerWriter; i++ {
+				if err := r.clicks.CreateClick(&models.Click{LinkID: link.ID, Timestamp: time.Now()}); err != nil {
+					t.Errorf("create click: %v", err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < clicksPerWriter; i++ {
+				if _, err := r.links.ListLinks(LinkFilter{SortBy: SortByClicks, Limit: 10}); err != nil {
+					t.Errorf("list links: %v", err)
+					return
+				}
+				if _, err := r.links.GetLinkByShortCode("hot"); err != nil {
+					t.Errorf("get link: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if count, err := r.links.CountClicksByLinkID(link.ID); err != nil || count != writers*clicksPerWriter {
+		t.Fatalf("count clicks: got %d, %v, want %d", count, err, writers*clicksPerWriter)
+	}
+}
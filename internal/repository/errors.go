@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// ErrNoFieldToUpdate est retournée par UpdateLink quand aucun champ à écrire n'est nommé.
+var ErrNoFieldToUpdate = errors.New("no link field to update")
+
+// Codes d'erreur transitoires des moteurs serveur.
+const (
+	mysqlLockWaitTimeout = 1205    // ER_LOCK_WAIT_TIMEOUT
+	mysqlDeadlock        = 1213    // ER_LOCK_DEADLOCK
+	pgSerializationError = "40001" // serialization_failure
+	pgDeadlockDetected   = "40P01" // deadlock_detected
+	pgLockNotAvailable   = "55P03" // lock_not_available
+)
+
+// Codes des violations de contrainte d'unicité.
+const (
+	mysqlDuplicateEntry = 1062    // ER_DUP_ENTRY
+	pgUniqueViolation   = "23505" // unique_violation
+)
+
+// IsBusyError indique si une erreur provient d'un verrou temporaire de la base
+// (SQLITE_BUSY / SQLITE_LOCKED, attente de verrou ou deadlock sous MySQL et PostgreSQL) :
+// l'opération peut alors être réessayée.
+func IsBusyError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlLockWaitTimeout || mysqlErr.Number == mysqlDeadlock
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgSerializationError || pgErr.Code == pgDeadlockDetected || pgErr.Code == pgLockNotAvailable
+	}
+	// Certaines erreurs sont ré-emballées sous forme de texte par les couches intermédiaires.
+	return strings.Contains(err.Error(), "database is locked") || strings.Contains(err.Error(), "database table is locked")
+}
+
+// IsDuplicateKeyError indique si une erreur provient d'une violation de contrainte d'unicité.
+// Elle reconnaît gorm.ErrDuplicatedKey (connexion ouverte avec TranslateError) comme les erreurs
+// brutes des drivers, pour ne pas dépendre de la façon dont la connexion a été ouverte.
+func IsDuplicateKeyError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDuplicateEntry
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgUniqueViolation
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// LinkAuditRepository définit les méthodes de lecture du journal d'audit des liens.
+// Les entrées sont écrites avec la modification qu'elles décrivent (voir UpdateLinkHealth, UpdateLink et DeleteLink).
+type LinkAuditRepository interface {
+	GetAuditEvents(linkID uint, limit int) ([]models.LinkAuditEvent, error)
+}
+
+// GormLinkAuditRepository est l'implémentation de LinkAuditRepository utilisant GORM.
+type GormLinkAuditRepository struct {
+	db *gorm.DB
+}
+
+// NewLinkAuditRepository crée et retourne une nouvelle instance de GormLinkAuditRepository.
+func NewLinkAuditRepository(db *gorm.DB) *GormLinkAuditRepository {
+	return &GormLinkAuditRepository{db: db}
+}
+
+// GetAuditEvents retourne les dernières entrées d'audit d'un lien, de la plus récente à la plus ancienne.
+func (r *GormLinkAuditRepository) GetAuditEvents(linkID uint, limit int) ([]models.LinkAuditEvent, error) {
+	var events []models.LinkAuditEvent
+	err := r.db.Where("link_id = ?", linkID).Order("created_at DESC, id DESC").Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link audit events: %w", err)
+	}
+	return events, nil
+}
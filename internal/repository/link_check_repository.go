@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// LinkCheckRepository définit les méthodes d'accès à l'historique des vérifications du moniteur.
+type LinkCheckRepository interface {
+	CreateLinkCheck(check *models.LinkCheck) error
+	GetLatestChecks() (map[uint]models.LinkCheck, error)
+	GetRecentChecks(linkID uint, limit int) ([]models.LinkCheck, error)
+	CountChecksSince(linkID uint, since time.Time) (total int64, up int64, err error)
+	DeleteChecksBefore(before time.Time) (int64, error)
+}
+
+// GormLinkCheckRepository est l'implémentation de LinkCheckRepository utilisant GORM.
+type GormLinkCheckRepository struct {
+	db *gorm.DB
+}
+
+// NewLinkCheckRepository crée et retourne une nouvelle instance de GormLinkCheckRepository.
+func NewLinkCheckRepository(db *gorm.DB) *GormLinkCheckRepository {
+	return &GormLinkCheckRepository{db: db}
+}
+
+// CreateLinkCheck enregistre le résultat d'une vérification.
+func (r *GormLinkCheckRepository) CreateLinkCheck(check *models.LinkCheck) error {
+	if err := r.db.Create(check).Error; err != nil {
+		return fmt.Errorf("failed to create link check: %w", err)
+	}
+	return nil
+}
+
+// GetLatestChecks retourne, pour chaque lien déjà vérifié, sa dernière vérification.
+func (r *GormLinkCheckRepository) GetLatestChecks() (map[uint]models.LinkCheck, error) {
+	var checks []models.LinkCheck
+	latest := r.db.Model(&models.LinkCheck{}).Select("MAX(id)").Group("link_id")
+	if err := r.db.Where("id IN (?)", latest).Find(&checks).Error; err != nil {
+		return nil, fmt.Errorf("failed to get latest link checks: %w", err)
+	}
+
+	byLink := make(map[uint]models.LinkCheck, len(checks))
+	for _, check := range checks {
+		byLink[check.LinkID] = check
+	}
+	return byLink, nil
+}
+
+// GetRecentChecks retourne les dernières vérifications d'un lien, de la plus récente à la plus ancienne.
+func (r *GormLinkCheckRepository) GetRecentChecks(linkID uint, limit int) ([]models.LinkCheck, error) {
+	var checks []models.LinkCheck
+	err := r.db.Where("link_id = ?", linkID).Order("checked_at DESC, id DESC").Limit(limit).Find(&checks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent link checks: %w", err)
+	}
+	return checks, nil
+}
+
+// CountChecksSince compte les vérifications d'un lien depuis since, et parmi elles celles réussies.
+func (r *GormLinkCheckRepository) CountChecksSince(linkID uint, since time.Time) (int64, int64, error) {
+	var counts struct {
+		Total int64
+		Up    int64
+	}
+	err := r.db.Model(&models.LinkCheck{}).
+		Select("COUNT(*) AS total, COALESCE(SUM(CASE WHEN up THEN 1 ELSE 0 END), 0) AS up").
+		Where("link_id = ? AND checked_at >= ?", linkID, since.UTC()).
+		Scan(&counts).Error
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count link checks: %w", err)
+	}
+	return counts.Total, counts.Up, nil
+}
+
+// DeleteChecksBefore supprime les vérifications antérieures à before et retourne leur nombre.
+func (r *GormLinkCheckRepository) DeleteChecksBefore(before time.Time) (int64, error) {
+	result := r.db.Where("checked_at < ?", before.UTC()).Delete(&models.LinkCheck{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete old link checks: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
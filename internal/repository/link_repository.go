@@ -2,6 +2,9 @@ package repository
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/axellelanca/urlshortener/internal/models"
 	"gorm.io/gorm"
@@ -11,11 +14,53 @@ import (
 // pour les opérations CRUD sur les liens.
 type LinkRepository interface {
 	CreateLink(link *models.Link) error
+	UpdateLink(link *models.Link, event *models.LinkAuditEvent, fields ...string) error
+	DeleteLink(link *models.Link, event *models.LinkAuditEvent) error
 	GetLinkByShortCode(shortCode string) (*models.Link, error)
+	ShortCodeExists(shortCode string) (bool, error)
 	GetAllLinks() ([]models.Link, error)
+	GetActiveLinks() ([]models.Link, error)
+	ListLinks(filter LinkFilter) ([]LinkSummary, error)
+	MarkExpiredLinks(now time.Time) (int64, error)
+	UpdateLinkHealth(link *models.Link, event *models.LinkAuditEvent) error
 	CountClicksByLinkID(linkID uint) (int, error)
+	ReserveClick(linkID uint) (bool, error)
 }
 
+// Critères de tri et statuts acceptés par ListLinks.
+const (
+	SortByCreatedAt = "created_at"
+	SortByClicks    = "clicks"
+
+	StatusActive   = "active"
+	StatusDisabled = "disabled"
+	StatusExpired  = "expired"
+)
+
+// LinkFilter décrit une page de liens à récupérer : filtres, tri et position du curseur.
+// La pagination se fait par curseur (keyset) sur le couple (clé de tri, ID) pour rester
+// stable même si des liens sont créés entre deux pages.
+type LinkFilter struct {
+	Domain      string     // Domaine de destination (sous-domaines inclus), vide = tous
+	CreatedFrom *time.Time // Borne inférieure incluse sur la date de création
+	CreatedTo   *time.Time // Borne supérieure exclue sur la date de création
+	Status      string     // StatusActive, StatusDisabled, StatusExpired ou vide pour tous
+	SortBy      string     // SortByCreatedAt ou SortByClicks
+	Descending  bool       // Ordre décroissant
+	AfterID     uint       // ID du dernier lien de la page précédente (0 = première page)
+	AfterClicks int        // Nombre de clics du dernier lien de la page précédente (tri par clics)
+	Limit       int        // Nombre maximal de liens à retourner
+}
+
+// LinkSummary est un lien accompagné de son nombre total de clics.
+type LinkSummary struct {
+	models.Link
+	ClickCount int
+}
+
+// clickCountSQL est la sous-requête qui calcule le nombre de clics d'un lien.
+const clickCountSQL = "(SELECT COUNT(*) FROM clicks WHERE clicks.link_id = links.id)"
+
 // GormLinkRepository est l'implémentation de LinkRepository utilisant GORM.
 type GormLinkRepository struct {
 	db *gorm.DB // Ajout de la référence à la base de données
@@ -39,6 +84,48 @@ func (r *GormLinkRepository) CreateLink(link *models.Link) error {
 	return nil
 }
 
+// UpdateLink enregistre les champs nommés (noms des champs Go, ex: "LongURL") d'un lien
+// existant et, si event n'est pas nil, l'entrée d'audit correspondante, dans une même
+// transaction. Les autres colonnes ne sont pas écrites : une copie périmée du lien n'écrase
+// pas l'expiration posée par le sweeper ni l'état de santé posé par le moniteur.
+func (r *GormLinkRepository) UpdateLink(link *models.Link, event *models.LinkAuditEvent, fields ...string) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("failed to update link: %w", ErrNoFieldToUpdate)
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(link).Select(append(slices.Clip(fields), "UpdatedAt")).Updates(link).Error; err != nil {
+			return err
+		}
+		if event != nil {
+			return tx.Create(event).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update link: %w", err)
+	}
+	return nil
+}
+
+// DeleteLink supprime logiquement un lien (renseigne DeletedAt) et, si event n'est pas nil,
+// enregistre l'entrée d'audit correspondante dans la même transaction.
+// Les clics associés sont conservés pour l'historique.
+func (r *GormLinkRepository) DeleteLink(link *models.Link, event *models.LinkAuditEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(link).Error; err != nil {
+			return err
+		}
+		if event != nil {
+			return tx.Create(event).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete link: %w", err)
+	}
+	return nil
+}
+
 // GetLinkByShortCode récupère un lien de la base de données en utilisant son shortCode.
 // Il renvoie gorm.ErrRecordNotFound si aucun lien n'est trouvé avec ce shortCode.
 func (r *GormLinkRepository) GetLinkByShortCode(shortCode string) (*models.Link, error) {
@@ -55,8 +142,18 @@ func (r *GormLinkRepository) GetLinkByShortCode(shortCode string) (*models.Link,
 	return &link, nil
 }
 
+// ShortCodeExists indique si un code court est déjà utilisé, y compris par un lien supprimé.
+// Les codes des liens supprimés ne sont jamais réattribués.
+func (r *GormLinkRepository) ShortCodeExists(shortCode string) (bool, error) {
+	var count int64
+	result := r.db.Unscoped().Model(&models.Link{}).Where("short_code = ?", shortCode).Count(&count)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to check short code existence: %w", result.Error)
+	}
+	return count > 0, nil
+}
+
 // GetAllLinks récupère tous les liens de la base de données.
-// Cette méthode est utilisée par le moniteur d'URLs.
 func (r *GormLinkRepository) GetAllLinks() ([]models.Link, error) {
 	var links []models.Link
 	// TODO 3: Utiliser GORM pour récupérer tous les liens.
@@ -67,6 +164,127 @@ func (r *GormLinkRepository) GetAllLinks() ([]models.Link, error) {
 	return links, nil
 }
 
+// GetActiveLinks récupère les liens qui ne sont ni expirés ni désactivés.
+// Cette méthode est utilisée par le moniteur d'URLs.
+func (r *GormLinkRepository) GetActiveLinks() ([]models.Link, error) {
+	var links []models.Link
+	result := r.db.Where("expired = ? AND disabled = ?", false, false).Find(&links)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get active links: %w", result.Error)
+	}
+	return links, nil
+}
+
+// ListLinks récupère une page de liens avec leur nombre de clics selon les critères du filtre.
+// L'ordre de création correspond à l'ordre des identifiants auto-incrémentés, ce qui permet
+// d'utiliser l'ID comme clé de tri pour SortByCreatedAt.
+func (r *GormLinkRepository) ListLinks(filter LinkFilter) ([]LinkSummary, error) {
+	query := r.db.Model(&models.Link{}).Select("links.*, " + clickCountSQL + " AS click_count")
+
+	if filter.Domain != "" {
+		query = query.Where(domainCondition(r.db, filter.Domain))
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("links.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("links.created_at < ?", *filter.CreatedTo)
+	}
+
+	switch filter.Status {
+	case StatusActive:
+		query = query.Where("links.disabled = ? AND links.expired = ?", false, false).
+			Where("links.expires_at IS NULL OR links.expires_at > ?", time.Now())
+	case StatusDisabled:
+		query = query.Where("links.disabled = ?", true)
+	case StatusExpired:
+		query = query.Where("links.expired = ? OR links.expires_at <= ?", true, time.Now())
+	}
+
+	// Comparaison keyset : strictement après (ou avant en ordre décroissant) le curseur.
+	cmp, direction := ">", "ASC"
+	if filter.Descending {
+		cmp, direction = "<", "DESC"
+	}
+	if filter.SortBy == SortByClicks {
+		if filter.AfterID != 0 {
+			query = query.Where(clickCountSQL+" "+cmp+" ? OR ("+clickCountSQL+" = ? AND links.id "+cmp+" ?)",
+				filter.AfterClicks, filter.AfterClicks, filter.AfterID)
+		}
+		query = query.Order("click_count " + direction).Order("links.id " + direction)
+	} else {
+		if filter.AfterID != 0 {
+			query = query.Where("links.id "+cmp+" ?", filter.AfterID)
+		}
+		query = query.Order("links.id " + direction)
+	}
+
+	var summaries []LinkSummary
+	result := query.Limit(filter.Limit).Scan(&summaries)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list links: %w", result.Error)
+	}
+	return summaries, nil
+}
+
+// likeEscaper échappe les jokers de LIKE (et le caractère d'échappement lui-même) pour qu'une
+// valeur saisie par l'utilisateur soit comparée littéralement. '!' est utilisé plutôt que '\',
+// qui est déjà un caractère d'échappement dans les chaînes MySQL.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// domainCondition construit la condition SQL qui sélectionne les URLs longues dont l'hôte
+// est exactement le domaine donné ou l'un de ses sous-domaines.
+func domainCondition(db *gorm.DB, domain string) *gorm.DB {
+	domain = likeEscaper.Replace(strings.ToLower(domain))
+	condition := db
+	for _, prefix := range []string{"://", "."} {
+		// Un hôte se termine par la fin de l'URL, un port, un chemin, une requête ou un fragment.
+		for _, suffix := range []string{"", ":%", "/%", "?%", "#%"} {
+			condition = condition.Or("LOWER(links.long_url) LIKE ? ESCAPE '!'", "%"+prefix+domain+suffix)
+		}
+	}
+	return condition
+}
+
+// MarkExpiredLinks marque comme expirés les liens dont la date d'expiration est dépassée
+// ou dont le budget de clics est épuisé. Le budget est comparé aux redirections réservées
+// et non aux clics enregistrés, qui peuvent encore attendre dans le tampon d'écriture.
+// Elle retourne le nombre de liens mis à jour.
+func (r *GormLinkRepository) MarkExpiredLinks(now time.Time) (int64, error) {
+	result := r.db.Model(&models.Link{}).
+		Where("expired = ?", false).
+		Where(r.db.Where("expires_at IS NOT NULL AND expires_at <= ?", now).
+			Or("max_clicks > 0 AND reserved_clicks >= max_clicks")).
+		Update("expired", true)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to mark expired links: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// UpdateLinkHealth enregistre l'état de santé d'un lien (DownSince et HealthAction) et,
+// si event n'est pas nil, l'entrée d'audit correspondante, dans une même transaction.
+// Les autres champs du lien ne sont pas modifiés.
+func (r *GormLinkRepository) UpdateLinkHealth(link *models.Link, event *models.LinkAuditEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Link{}).Where("id = ?", link.ID).UpdateColumns(map[string]any{
+			"down_since":    link.DownSince,
+			"health_action": link.HealthAction,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if event != nil {
+			return tx.Create(event).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update link health: %w", err)
+	}
+	return nil
+}
+
 // CountClicksByLinkID compte le nombre total de clics pour un ID de lien donné.
 func (r *GormLinkRepository) CountClicksByLinkID(linkID uint) (int, error) {
 	var count int64 // GORM retourne un int64 pour les comptes
@@ -78,4 +296,16 @@ func (r *GormLinkRepository) CountClicksByLinkID(linkID uint) (int, error) {
 	}
 
 	return int(count), nil
-}
\ No newline at end of file
+}
+
+// ReserveClick décompte une redirection du budget de clics d'un lien, de façon atomique.
+// Elle retourne false si le budget est déjà épuisé (ou si le lien n'a pas de budget).
+func (r *GormLinkRepository) ReserveClick(linkID uint) (bool, error) {
+	result := r.db.Model(&models.Link{}).
+		Where("id = ? AND max_clicks > 0 AND reserved_clicks < max_clicks", linkID).
+		UpdateColumn("reserved_clicks", gorm.Expr("reserved_clicks + 1"))
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to reserve click for link ID %d: %w", linkID, result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
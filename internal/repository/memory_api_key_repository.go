@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// MemoryAPIKeyRepository est l'implémentation en mémoire de APIKeyRepository.
+type MemoryAPIKeyRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryAPIKeyRepository crée un repository de clés d'API sur le stockage en mémoire donné.
+func NewMemoryAPIKeyRepository(store *MemoryStore) *MemoryAPIKeyRepository {
+	return &MemoryAPIKeyRepository{store: store}
+}
+
+// cloneAPIKey copie une clé d'API, y compris les dates pointées.
+func cloneAPIKey(key models.APIKey) models.APIKey {
+	key.LastUsedAt = cloneTime(key.LastUsedAt)
+	key.RevokedAt = cloneTime(key.RevokedAt)
+	return key
+}
+
+// findAPIKey retourne la position d'une clé d'après son ID, -1 si elle n'existe pas.
+// L'appelant doit détenir le verrou.
+func (s *MemoryStore) findAPIKey(id uint) int {
+	i := int(id) - 1
+	if id == 0 || i >= len(s.apiKeys) {
+		return -1
+	}
+	return i
+}
+
+// CreateAPIKey enregistre une nouvelle clé d'API.
+func (r *MemoryAPIKeyRepository) CreateAPIKey(key *models.APIKey) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.apiKeys {
+		if existing.KeyHash == key.KeyHash {
+			return fmt.Errorf("failed to create api key: %w", gorm.ErrDuplicatedKey)
+		}
+	}
+	s.lastAPIKeyID++
+	key.ID = s.lastAPIKeyID
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+	s.apiKeys = append(s.apiKeys, cloneAPIKey(*key))
+	return nil
+}
+
+// GetAPIKeyByHash récupère une clé d'API par le hash de sa valeur.
+// Il renvoie gorm.ErrRecordNotFound si aucune clé ne correspond.
+func (r *MemoryAPIKeyRepository) GetAPIKeyByHash(hash string) (*models.APIKey, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, key := range s.apiKeys {
+		if key.KeyHash == hash {
+			key = cloneAPIKey(key)
+			return &key, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// GetAPIKeyByID récupère une clé d'API par son identifiant.
+// Il renvoie gorm.ErrRecordNotFound si la clé n'existe pas.
+func (r *MemoryAPIKeyRepository) GetAPIKeyByID(id uint) (*models.APIKey, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := s.findAPIKey(id)
+	if i < 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	key := cloneAPIKey(s.apiKeys[i])
+	return &key, nil
+}
+
+// ListAPIKeys récupère toutes les clés d'API, révoquées comprises, par ordre de création.
+func (r *MemoryAPIKeyRepository) ListAPIKeys() ([]models.APIKey, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]models.APIKey, 0, len(s.apiKeys))
+	for _, key := range s.apiKeys {
+		keys = append(keys, cloneAPIKey(key))
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey révoque une clé d'API. Une clé déjà révoquée conserve sa date de révocation.
+// Il renvoie gorm.ErrRecordNotFound si la clé n'existe pas.
+func (r *MemoryAPIKeyRepository) RevokeAPIKey(id uint, at time.Time) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.findAPIKey(id)
+	if i < 0 {
+		return gorm.ErrRecordNotFound
+	}
+	if s.apiKeys[i].RevokedAt == nil {
+		s.apiKeys[i].RevokedAt = &at
+	}
+	return nil
+}
+
+// TouchAPIKey enregistre la date de dernière utilisation d'une clé d'API.
+func (r *MemoryAPIKeyRepository) TouchAPIKey(id uint, at time.Time) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := s.findAPIKey(id); i >= 0 {
+		s.apiKeys[i].LastUsedAt = &at
+	}
+	return nil
+}
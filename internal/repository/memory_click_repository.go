@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/hll"
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+// MemoryClickRepository est l'implémentation en mémoire de ClickRepository.
+type MemoryClickRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryClickRepository crée un repository de clics sur le stockage en mémoire donné.
+func NewMemoryClickRepository(store *MemoryStore) *MemoryClickRepository {
+	return &MemoryClickRepository{store: store}
+}
+
+// breakdownValue retourne la valeur d'un clic pour une dimension de répartition.
+func breakdownValue(click models.Click, dimension string) string {
+	switch dimension {
+	case DimensionReferrer:
+		return click.ReferrerHost
+	case DimensionBrowser:
+		return click.Browser
+	case DimensionOS:
+		return click.OS
+	default:
+		return click.DeviceType
+	}
+}
+
+// addClick enregistre un clic et lui attribue un identifiant. L'appelant doit détenir le verrou en écriture.
+func (s *MemoryStore) addClick(click *models.Click) {
+	s.lastClickID++
+	click.ID = s.lastClickID
+	stored := *click
+	stored.Link = models.Link{} // La relation n'est pas stockée, seule la clé étrangère compte
+	s.clicks = append(s.clicks, stored)
+	s.clickCounts[click.LinkID]++
+}
+
+// CreateClick insère un nouveau clic.
+func (r *MemoryClickRepository) CreateClick(click *models.Click) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.addClick(click)
+	return nil
+}
+
+// CountClicksByLinkID compte le nombre total de clics pour un ID de lien donné.
+func (r *MemoryClickRepository) CountClicksByLinkID(linkID uint) (int, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.clickCounts[linkID], nil
+}
+
+// CountClicksBySlot compte les clics d'un lien dans l'intervalle [from, to[, regroupés en tranches
+// de durée slot numérotées à partir de origin (qui doit précéder from). Seules les tranches
+// contenant au moins un clic sont retournées, par ordre croissant.
+func (r *MemoryClickRepository) CountClicksBySlot(linkID uint, from, to, origin time.Time, slot time.Duration) ([]SlotCount, error) {
+	s := r.store
+	s.mu.RLock()
+	bySlot := make(map[int64]int)
+	for _, click := range s.clicks {
+		if click.LinkID == linkID && !click.Timestamp.Before(from) && click.Timestamp.Before(to) {
+			// Comme en base, l'horodatage est arrondi à la seconde inférieure.
+			bySlot[(click.Timestamp.Unix()-origin.Unix())/int64(slot/time.Second)]++
+		}
+	}
+	s.mu.RUnlock()
+
+	counts := make([]SlotCount, 0, len(bySlot))
+	for slot, clicks := range bySlot {
+		counts = append(counts, SlotCount{Slot: slot, Clicks: clicks})
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Slot < counts[j].Slot })
+	return counts, nil
+}
+
+// GetClickBreakdown retourne les valeurs les plus fréquentes d'une dimension pour un lien,
+// triées par nombre de clics décroissant puis par valeur.
+func (r *MemoryClickRepository) GetClickBreakdown(linkID uint, dimension string, limit int) ([]BreakdownEntry, error) {
+	if _, ok := breakdownColumns[dimension]; !ok {
+		return nil, fmt.Errorf("unknown breakdown dimension %q", dimension)
+	}
+
+	s := r.store
+	s.mu.RLock()
+	counts := make(map[string]int)
+	for _, click := range s.clicks {
+		if click.LinkID == linkID {
+			counts[breakdownValue(click, dimension)]++
+		}
+	}
+	s.mu.RUnlock()
+
+	entries := make([]BreakdownEntry, 0, len(counts))
+	for value, clicks := range counts {
+		entries = append(entries, BreakdownEntry{Value: value, Clicks: clicks})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Clicks != entries[j].Clicks {
+			return entries[i].Clicks > entries[j].Clicks
+		}
+		return entries[i].Value < entries[j].Value
+	})
+	if limit >= 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// CreateClickBatch insère un lot de clics et fusionne les sketches de visiteurs associés,
+// de façon atomique.
+func (r *MemoryClickRepository) CreateClickBatch(clicks []models.Click, visitors map[uint]*hll.Sketch) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Les sketches sont décodés avant toute écriture : une erreur laisse le stockage intact.
+	merged := make(map[uint]*hll.Sketch, len(visitors))
+	for linkID, sketch := range visitors {
+		current := hll.New()
+		if stored, ok := s.sketches[linkID]; ok {
+			existing, err := hll.FromBytes(stored)
+			if err != nil {
+				return fmt.Errorf("failed to create click batch of %d click(s): %w", len(clicks), err)
+			}
+			current.Merge(existing)
+		}
+		current.Merge(sketch)
+		merged[linkID] = current
+	}
+
+	for i := range clicks {
+		s.addClick(&clicks[i])
+	}
+	for linkID, sketch := range merged {
+		s.sketches[linkID] = sketch.Bytes()
+	}
+	return nil
+}
+
+// GetVisitorSketch récupère le sketch des visiteurs d'un lien.
+// Un lien sans visiteur enregistré retourne un sketch vide.
+func (r *MemoryClickRepository) GetVisitorSketch(linkID uint) (*hll.Sketch, error) {
+	s := r.store
+	s.mu.RLock()
+	stored, ok := s.sketches[linkID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return hll.New(), nil
+	}
+	sketch, err := hll.FromBytes(stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode visitor sketch for link ID %d: %w", linkID, err)
+	}
+	return sketch, nil
+}
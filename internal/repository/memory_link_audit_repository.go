@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"sort"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+// MemoryLinkAuditRepository est l'implémentation en mémoire de LinkAuditRepository.
+// Les entrées sont écrites par MemoryLinkRepository (UpdateLinkHealth, UpdateLink, DeleteLink)
+// sur le même stockage.
+type MemoryLinkAuditRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryLinkAuditRepository crée un repository d'audit sur le stockage en mémoire donné.
+func NewMemoryLinkAuditRepository(store *MemoryStore) *MemoryLinkAuditRepository {
+	return &MemoryLinkAuditRepository{store: store}
+}
+
+// GetAuditEvents retourne les dernières entrées d'audit d'un lien, de la plus récente à la plus ancienne.
+func (r *MemoryLinkAuditRepository) GetAuditEvents(linkID uint, limit int) ([]models.LinkAuditEvent, error) {
+	s := r.store
+	s.mu.RLock()
+	var events []models.LinkAuditEvent
+	for _, event := range s.auditEvents {
+		if event.LinkID == linkID {
+			events = append(events, event)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].CreatedAt.After(events[j].CreatedAt)
+		}
+		return events[i].ID > events[j].ID
+	})
+	if limit >= 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
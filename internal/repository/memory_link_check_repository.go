@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+// MemoryLinkCheckRepository est l'implémentation en mémoire de LinkCheckRepository.
+type MemoryLinkCheckRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryLinkCheckRepository crée un repository de vérifications sur le stockage en mémoire donné.
+func NewMemoryLinkCheckRepository(store *MemoryStore) *MemoryLinkCheckRepository {
+	return &MemoryLinkCheckRepository{store: store}
+}
+
+// cloneLinkCheck copie une vérification, y compris sa chaîne de redirections.
+func cloneLinkCheck(check models.LinkCheck) models.LinkCheck {
+	check.RedirectChain = slices.Clone(check.RedirectChain)
+	return check
+}
+
+// CreateLinkCheck enregistre le résultat d'une vérification.
+func (r *MemoryLinkCheckRepository) CreateLinkCheck(check *models.LinkCheck) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastCheckID++
+	check.ID = s.lastCheckID
+	s.checks = append(s.checks, cloneLinkCheck(*check))
+	return nil
+}
+
+// GetLatestChecks retourne, pour chaque lien déjà vérifié, sa dernière vérification.
+func (r *MemoryLinkCheckRepository) GetLatestChecks() (map[uint]models.LinkCheck, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Les vérifications sont rangées par ID croissant : la dernière lue est la plus récente.
+	byLink := make(map[uint]models.LinkCheck)
+	for _, check := range s.checks {
+		byLink[check.LinkID] = cloneLinkCheck(check)
+	}
+	return byLink, nil
+}
+
+// GetRecentChecks retourne les dernières vérifications d'un lien, de la plus récente à la plus ancienne.
+func (r *MemoryLinkCheckRepository) GetRecentChecks(linkID uint, limit int) ([]models.LinkCheck, error) {
+	s := r.store
+	s.mu.RLock()
+	var checks []models.LinkCheck
+	for _, check := range s.checks {
+		if check.LinkID == linkID {
+			checks = append(checks, cloneLinkCheck(check))
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(checks, func(i, j int) bool {
+		if !checks[i].CheckedAt.Equal(checks[j].CheckedAt) {
+			return checks[i].CheckedAt.After(checks[j].CheckedAt)
+		}
+		return checks[i].ID > checks[j].ID
+	})
+	if limit >= 0 && len(checks) > limit {
+		checks = checks[:limit]
+	}
+	return checks, nil
+}
+
+// CountChecksSince compte les vérifications d'un lien depuis since, et parmi elles celles réussies.
+func (r *MemoryLinkCheckRepository) CountChecksSince(linkID uint, since time.Time) (int64, int64, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var total, up int64
+	for _, check := range s.checks {
+		if check.LinkID == linkID && !check.CheckedAt.Before(since) {
+			total++
+			if check.Up {
+				up++
+			}
+		}
+	}
+	return total, up, nil
+}
+
+// DeleteChecksBefore supprime les vérifications antérieures à before et retourne leur nombre.
+func (r *MemoryLinkCheckRepository) DeleteChecksBefore(before time.Time) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.checks[:0]
+	for _, check := range s.checks {
+		if !check.CheckedAt.Before(before) {
+			kept = append(kept, check)
+		}
+	}
+	deleted := int64(len(s.checks) - len(kept))
+	clear(s.checks[len(kept):])
+	s.checks = kept
+	return deleted, nil
+}
@@ -0,0 +1,342 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// MemoryLinkRepository est l'implémentation en mémoire de LinkRepository.
+// Elle reproduit les erreurs de l'implémentation GORM (gorm.ErrRecordNotFound,
+// gorm.ErrDuplicatedKey) pour que les services la traitent de la même façon.
+type MemoryLinkRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryLinkRepository crée un repository de liens sur le stockage en mémoire donné.
+func NewMemoryLinkRepository(store *MemoryStore) *MemoryLinkRepository {
+	return &MemoryLinkRepository{store: store}
+}
+
+// cloneLink copie un lien, y compris les dates pointées, pour que l'appelant
+// ne partage aucune donnée avec le stockage.
+func cloneLink(link models.Link) models.Link {
+	link.ExpiresAt = cloneTime(link.ExpiresAt)
+	link.DownSince = cloneTime(link.DownSince)
+	return link
+}
+
+// cloneTime copie une date optionnelle.
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
+
+// CreateLink insère un nouveau lien et lui attribue un identifiant.
+func (r *MemoryLinkRepository) CreateLink(link *models.Link) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.linkIndex[link.ShortCode]; ok {
+		return fmt.Errorf("failed to create link: %w", gorm.ErrDuplicatedKey)
+	}
+
+	now := time.Now()
+	s.lastLinkID++
+	link.ID = s.lastLinkID
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = now
+	}
+	if link.UpdatedAt.IsZero() {
+		link.UpdatedAt = now
+	}
+	s.linkIndex[link.ShortCode] = len(s.links)
+	s.links = append(s.links, cloneLink(*link))
+	return nil
+}
+
+// UpdateLink enregistre les champs nommés d'un lien existant et, si event n'est pas nil,
+// l'entrée d'audit correspondante, de façon atomique (voir GormLinkRepository.UpdateLink).
+func (r *MemoryLinkRepository) UpdateLink(link *models.Link, event *models.LinkAuditEvent, fields ...string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(fields) == 0 {
+		return fmt.Errorf("failed to update link: %w", ErrNoFieldToUpdate)
+	}
+	i := int(link.ID) - 1
+	if link.ID == 0 || i >= len(s.links) {
+		return fmt.Errorf("failed to update link: %w", gorm.ErrRecordNotFound)
+	}
+
+	stored := &s.links[i]
+	for _, field := range fields {
+		switch field {
+		case "LongURL":
+			stored.LongURL = link.LongURL
+		case "Disabled":
+			stored.Disabled = link.Disabled
+		case "FallbackURL":
+			stored.FallbackURL = link.FallbackURL
+		case "RedirectType":
+			stored.RedirectType = link.RedirectType
+		case "ExpiresAt":
+			stored.ExpiresAt = cloneTime(link.ExpiresAt)
+		case "MaxClicks":
+			stored.MaxClicks = link.MaxClicks
+		default:
+			return fmt.Errorf("failed to update link: unsupported field %q", field)
+		}
+	}
+	stored.UpdatedAt = time.Now()
+	link.UpdatedAt = stored.UpdatedAt
+	if event != nil {
+		s.addAuditEvent(event)
+	}
+	return nil
+}
+
+// DeleteLink supprime logiquement un lien (renseigne DeletedAt) et, si event n'est pas nil,
+// enregistre l'entrée d'audit correspondante, de façon atomique.
+// Les clics associés sont conservés pour l'historique.
+func (r *MemoryLinkRepository) DeleteLink(link *models.Link, event *models.LinkAuditEvent) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := s.findLink(link.ID); i >= 0 {
+		deletedAt := gorm.DeletedAt{Time: time.Now(), Valid: true}
+		s.links[i].DeletedAt = deletedAt
+		link.DeletedAt = deletedAt
+	}
+	if event != nil {
+		s.addAuditEvent(event)
+	}
+	return nil
+}
+
+// GetLinkByShortCode récupère un lien non supprimé par son code court.
+// Il renvoie gorm.ErrRecordNotFound si aucun lien n'est trouvé avec ce shortCode.
+func (r *MemoryLinkRepository) GetLinkByShortCode(shortCode string) (*models.Link, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i, ok := s.linkIndex[shortCode]
+	if !ok || s.links[i].DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	link := cloneLink(s.links[i])
+	return &link, nil
+}
+
+// ShortCodeExists indique si un code court est déjà utilisé, y compris par un lien supprimé.
+func (r *MemoryLinkRepository) ShortCodeExists(shortCode string) (bool, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.linkIndex[shortCode]
+	return ok, nil
+}
+
+// GetAllLinks récupère tous les liens non supprimés.
+func (r *MemoryLinkRepository) GetAllLinks() ([]models.Link, error) {
+	return r.selectLinks(func(models.Link) bool { return true }), nil
+}
+
+// GetActiveLinks récupère les liens qui ne sont ni expirés ni désactivés.
+func (r *MemoryLinkRepository) GetActiveLinks() ([]models.Link, error) {
+	return r.selectLinks(func(link models.Link) bool { return !link.Expired && !link.Disabled }), nil
+}
+
+// selectLinks retourne, par ordre d'ID, les liens non supprimés qui satisfont keep.
+func (r *MemoryLinkRepository) selectLinks(keep func(models.Link) bool) []models.Link {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var links []models.Link
+	for _, link := range s.links {
+		if !link.DeletedAt.Valid && keep(link) {
+			links = append(links, cloneLink(link))
+		}
+	}
+	return links
+}
+
+// ListLinks récupère une page de liens avec leur nombre de clics selon les critères du filtre,
+// avec la même pagination par curseur que l'implémentation GORM.
+func (r *MemoryLinkRepository) ListLinks(filter LinkFilter) ([]LinkSummary, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	domain := strings.ToLower(filter.Domain)
+	var summaries []LinkSummary
+	for _, link := range s.links {
+		if link.DeletedAt.Valid {
+			continue
+		}
+		if domain != "" && !matchesDomain(link.LongURL, domain) {
+			continue
+		}
+		if filter.CreatedFrom != nil && link.CreatedAt.Before(*filter.CreatedFrom) {
+			continue
+		}
+		if filter.CreatedTo != nil && !link.CreatedAt.Before(*filter.CreatedTo) {
+			continue
+		}
+		if !matchesStatus(link, filter.Status, now) {
+			continue
+		}
+
+		clicks := s.clickCounts[link.ID]
+		if filter.AfterID != 0 && !afterCursor(filter, link.ID, clicks) {
+			continue
+		}
+		summaries = append(summaries, LinkSummary{Link: cloneLink(link), ClickCount: clicks})
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if filter.SortBy == SortByClicks && a.ClickCount != b.ClickCount {
+			return ordered(a.ClickCount, b.ClickCount, filter.Descending)
+		}
+		return ordered(a.ID, b.ID, filter.Descending)
+	})
+	if filter.Limit >= 0 && len(summaries) > filter.Limit {
+		summaries = summaries[:filter.Limit]
+	}
+	return summaries, nil
+}
+
+// matchesDomain reproduit domainCondition : l'hôte de l'URL longue est exactement
+// le domaine donné (en minuscules) ou l'un de ses sous-domaines.
+func matchesDomain(longURL, domain string) bool {
+	longURL = strings.ToLower(longURL)
+	for _, prefix := range []string{"://", "."} {
+		host := prefix + domain
+		if strings.HasSuffix(longURL, host) {
+			return true
+		}
+		for _, end := range []string{":", "/", "?", "#"} {
+			if strings.Contains(longURL, host+end) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesStatus indique si un lien correspond au statut demandé par ListLinks.
+func matchesStatus(link models.Link, status string, now time.Time) bool {
+	switch status {
+	case StatusActive:
+		return !link.Disabled && !link.Expired && (link.ExpiresAt == nil || link.ExpiresAt.After(now))
+	case StatusDisabled:
+		return link.Disabled
+	case StatusExpired:
+		return link.Expired || (link.ExpiresAt != nil && !link.ExpiresAt.After(now))
+	default:
+		return true
+	}
+}
+
+// afterCursor indique si un lien se trouve strictement après le curseur du filtre
+// (avant en ordre décroissant).
+func afterCursor(filter LinkFilter, id uint, clicks int) bool {
+	if filter.SortBy == SortByClicks && clicks != filter.AfterClicks {
+		return ordered(filter.AfterClicks, clicks, filter.Descending)
+	}
+	return ordered(filter.AfterID, id, filter.Descending)
+}
+
+// ordered indique si a précède strictement b dans l'ordre demandé.
+func ordered[T int | uint](a, b T, descending bool) bool {
+	if descending {
+		return a > b
+	}
+	return a < b
+}
+
+// MarkExpiredLinks marque comme expirés les liens dont la date d'expiration est dépassée
+// ou dont le budget de clics est épuisé. Elle retourne le nombre de liens mis à jour.
+func (r *MemoryLinkRepository) MarkExpiredLinks(now time.Time) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var marked int64
+	updatedAt := time.Now()
+	for i := range s.links {
+		link := &s.links[i]
+		if link.DeletedAt.Valid || link.Expired {
+			continue
+		}
+		expired := link.ExpiresAt != nil && !link.ExpiresAt.After(now)
+		exhausted := link.MaxClicks > 0 && link.ReservedClicks >= link.MaxClicks
+		if expired || exhausted {
+			link.Expired = true
+			link.UpdatedAt = updatedAt
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+// UpdateLinkHealth enregistre l'état de santé d'un lien (DownSince et HealthAction) et,
+// si event n'est pas nil, l'entrée d'audit correspondante, de façon atomique.
+// Les autres champs du lien ne sont pas modifiés.
+func (r *MemoryLinkRepository) UpdateLinkHealth(link *models.Link, event *models.LinkAuditEvent) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := s.findLink(link.ID); i >= 0 {
+		s.links[i].DownSince = cloneTime(link.DownSince)
+		s.links[i].HealthAction = link.HealthAction
+	}
+	if event != nil {
+		s.addAuditEvent(event)
+	}
+	return nil
+}
+
+// CountClicksByLinkID compte le nombre total de clics pour un ID de lien donné.
+func (r *MemoryLinkRepository) CountClicksByLinkID(linkID uint) (int, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.clickCounts[linkID], nil
+}
+
+// ReserveClick décompte une redirection du budget de clics d'un lien, de façon atomique.
+// Elle retourne false si le budget est déjà épuisé (ou si le lien n'a pas de budget).
+func (r *MemoryLinkRepository) ReserveClick(linkID uint) (bool, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.findLink(linkID)
+	if i < 0 {
+		return false, nil
+	}
+	link := &s.links[i]
+	if link.MaxClicks == 0 || link.ReservedClicks >= link.MaxClicks {
+		return false, nil
+	}
+	link.ReservedClicks++
+	return true, nil
+}
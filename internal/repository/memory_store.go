@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+// MemoryStore contient les données des repositories en mémoire (database.driver: memory).
+// Les repositories créés sur un même MemoryStore partagent ses tables, comme les
+// repositories GORM partagent une base : le nombre de clics d'un lien ou l'audit
+// de santé sont ainsi visibles d'un repository à l'autre.
+// Toutes les données sont perdues à l'arrêt du processus.
+type MemoryStore struct {
+	mu sync.RWMutex // Protège toutes les tables ci-dessous
+
+	links       []models.Link   // Liens par ordre d'ID, supprimés logiquement compris
+	linkIndex   map[string]int  // Code court -> position dans links
+	clicks      []models.Click  // Clics par ordre d'ID
+	clickCounts map[uint]int    // Nombre de clics par lien
+	sketches    map[uint][]byte // Registres HyperLogLog sérialisés par lien
+	apiKeys     []models.APIKey
+	checks      []models.LinkCheck
+	auditEvents []models.LinkAuditEvent
+
+	// Dernier identifiant attribué par table, à la manière d'une colonne auto-incrémentée.
+	lastLinkID, lastClickID, lastAPIKeyID, lastCheckID, lastAuditEventID uint
+}
+
+// NewMemoryStore crée un stockage en mémoire vide.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		linkIndex:   make(map[string]int),
+		clickCounts: make(map[uint]int),
+		sketches:    make(map[uint][]byte),
+	}
+}
+
+// findLink retourne la position d'un lien non supprimé d'après son ID, -1 s'il n'existe pas.
+// Les identifiants étant attribués séquentiellement, un lien d'ID n est en position n-1.
+// L'appelant doit détenir le verrou.
+func (s *MemoryStore) findLink(id uint) int {
+	i := int(id) - 1
+	if id == 0 || i >= len(s.links) || s.links[i].DeletedAt.Valid {
+		return -1
+	}
+	return i
+}
+
+// addAuditEvent enregistre une entrée d'audit. L'appelant doit détenir le verrou en écriture.
+func (s *MemoryStore) addAuditEvent(event *models.LinkAuditEvent) {
+	s.lastAuditEventID++
+	event.ID = s.lastAuditEventID
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	s.auditEvents = append(s.auditEvents, *event)
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+)
+
+// Format des clés d'API : un préfixe fixe suivi d'un secret aléatoire.
+const (
+	apiKeyPrefix       = "usk_"
+	apiKeySecretLength = 40
+	apiKeyDisplayChars = 12 // Nombre de caractères conservés en clair pour identifier une clé
+)
+
+// apiKeyTouchInterval limite l'écriture de la date de dernière utilisation d'une clé.
+const apiKeyTouchInterval = time.Minute
+
+// Erreurs métier liées aux clés d'API.
+var (
+	ErrInvalidAPIKey = errors.New("invalid or revoked api key")
+	ErrInvalidScope  = errors.New("invalid api key scope")
+	ErrAPIKeyName    = errors.New("api key name is required")
+)
+
+// APIKeyService fournit la logique métier de création, de vérification et de révocation des clés d'API.
+type APIKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+// NewAPIKeyService crée et retourne une nouvelle instance de APIKeyService.
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// CreateAPIKey génère une nouvelle clé d'API avec les portées demandées.
+// Elle retourne la clé en clair, qui n'est plus récupérable ensuite, et l'enregistrement créé.
+func (s *APIKeyService) CreateAPIKey(name string, scopes []string) (string, *models.APIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil, ErrAPIKeyName
+	}
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	secret, err := randomString(apiKeySecretLength)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	raw := apiKeyPrefix + secret
+
+	key := &models.APIKey{
+		Name:    name,
+		Prefix:  raw[:apiKeyDisplayChars],
+		KeyHash: hashAPIKey(raw),
+		Scopes:  strings.Join(scopes, " "),
+	}
+	if err := s.apiKeyRepo.CreateAPIKey(key); err != nil {
+		return "", nil, err
+	}
+	return raw, key, nil
+}
+
+// Authenticate vérifie une clé d'API présentée par un client et retourne l'enregistrement associé.
+// Elle renvoie ErrInvalidAPIKey si la clé est inconnue ou révoquée.
+func (s *APIKeyService) Authenticate(raw string) (*models.APIKey, error) {
+	if !strings.HasPrefix(raw, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	key, err := s.apiKeyRepo.GetAPIKeyByHash(hashAPIKey(raw))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	if key.IsRevoked() {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// La date de dernière utilisation est indicative : inutile d'écrire à chaque requête.
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.apiKeyRepo.TouchAPIKey(key.ID, now); err != nil {
+			return nil, err
+		}
+		key.LastUsedAt = &now
+	}
+	return key, nil
+}
+
+// ListAPIKeys retourne toutes les clés d'API, révoquées comprises.
+func (s *APIKeyService) ListAPIKeys() ([]models.APIKey, error) {
+	return s.apiKeyRepo.ListAPIKeys()
+}
+
+// RevokeAPIKey révoque une clé d'API. Elle renvoie gorm.ErrRecordNotFound si la clé n'existe pas.
+func (s *APIKeyService) RevokeAPIKey(id uint) error {
+	return s.apiKeyRepo.RevokeAPIKey(id, time.Now())
+}
+
+// normalizeScopes vérifie que chaque portée est connue et retire les doublons.
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !slices.Contains(models.AllScopes, scope) {
+			return nil, fmt.Errorf("%w: %q (expected one of %s)", ErrInvalidScope, scope, strings.Join(models.AllScopes, ", "))
+		}
+		if !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
+
+// hashAPIKey calcule l'empreinte stockée d'une clé d'API.
+// Les clés étant longues et aléatoires, un SHA-256 simple suffit (pas besoin d'un hash lent).
+func hashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+)
+
+// Bornes du nombre d'entrées retournées par GetLinkAudit.
+const (
+	DefaultAuditLimit = 50
+	MaxAuditLimit     = 200
+)
+
+// ErrInvalidAuditParams est retournée quand le nombre d'entrées demandé est invalide.
+var ErrInvalidAuditParams = errors.New("invalid audit parameters")
+
+// AuditService fournit le journal d'audit des liens.
+type AuditService struct {
+	auditRepo repository.LinkAuditRepository
+}
+
+// NewAuditService crée et retourne une nouvelle instance de AuditService.
+func NewAuditService(auditRepo repository.LinkAuditRepository) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+	}
+}
+
+// GetLinkAudit retourne les limit dernières entrées d'audit d'un lien, de la plus récente
+// à la plus ancienne. Une limite nulle prend la valeur par défaut.
+func (s *AuditService) GetLinkAudit(linkID uint, limit int) ([]models.LinkAuditEvent, error) {
+	if limit == 0 {
+		limit = DefaultAuditLimit
+	}
+	if limit < 0 || limit > MaxAuditLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidAuditParams, MaxAuditLimit)
+	}
+	return s.auditRepo.GetAuditEvents(linkID, limit)
+}
@@ -1,12 +1,41 @@
 package services
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/axellelanca/urlshortener/internal/models"
 	"github.com/axellelanca/urlshortener/internal/repository" // Importe le package repository
 )
 
+// Granularités acceptées pour les séries temporelles de clics.
+const (
+	IntervalHour = "hour"
+	IntervalDay  = "day"
+	IntervalWeek = "week"
+)
+
+// maxTimeSeriesBuckets limite la taille d'une série pour éviter les réponses démesurées.
+const maxTimeSeriesBuckets = 2000
+
+// ErrInvalidTimeSeries est retournée quand la période ou la granularité demandée est invalide.
+var ErrInvalidTimeSeries = errors.New("invalid time series parameters")
+
+// TimeBucket représente le nombre de clics sur une période commençant à Start.
+type TimeBucket struct {
+	Start  time.Time `json:"start"`
+	Clicks int       `json:"clicks"`
+}
+
+// ClickBreakdowns regroupe les répartitions des clics d'un lien (top N par dimension).
+type ClickBreakdowns struct {
+	Referrers        []repository.BreakdownEntry `json:"referrers"`
+	Browsers         []repository.BreakdownEntry `json:"browsers"`
+	OperatingSystems []repository.BreakdownEntry `json:"operating_systems"`
+	Devices          []repository.BreakdownEntry `json:"devices"`
+}
+
 // TODO : créer la struct
 // ClickService est une structure qui fournit des méthodes pour la logique métier des clics.
 type ClickService struct {
@@ -40,4 +69,138 @@ func (s *ClickService) GetClicksCountByLinkID(linkID uint) (int, error) {
 		return 0, fmt.Errorf("failed to get clicks count: %w", err)
 	}
 	return count, nil
-}
\ No newline at end of file
+}
+
+// GetClickTimeSeries retourne le nombre de clics d'un lien regroupés par heure, jour ou semaine
+// sur l'intervalle [from, to[. Les périodes sont alignées dans le fuseau horaire loc
+// (les semaines commencent le lundi) et les périodes sans clic sont incluses avec un total nul.
+func (s *ClickService) GetClickTimeSeries(linkID uint, from, to time.Time, interval string, loc *time.Location) ([]TimeBucket, error) {
+	if interval != IntervalHour && interval != IntervalDay && interval != IntervalWeek {
+		return nil, fmt.Errorf("%w: unknown interval %q", ErrInvalidTimeSeries, interval)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: 'from' must be before 'to'", ErrInvalidTimeSeries)
+	}
+
+	// Construit la liste des périodes, y compris celles sans clic.
+	// La tranche de regroupement confiée à la base est le PGCD des durées des périodes :
+	// chaque début de période (heure locale, minuit local, changement d'heure) tombe ainsi
+	// sur un début de tranche, et chaque tranche appartient à une seule période.
+	var buckets []TimeBucket
+	index := make(map[int64]int)
+	var slot int64
+	for start := truncateToInterval(from, interval, loc); start.Before(to); {
+		if len(buckets) >= maxTimeSeriesBuckets {
+			return nil, fmt.Errorf("%w: more than %d buckets requested", ErrInvalidTimeSeries, maxTimeSeriesBuckets)
+		}
+		index[start.Unix()] = len(buckets)
+		buckets = append(buckets, TimeBucket{Start: start})
+		next := nextInterval(start, interval, loc)
+		slot = gcd(slot, next.Unix()-start.Unix())
+		start = next
+	}
+
+	origin := buckets[0].Start
+	counts, err := s.clickRepo.CountClicksBySlot(linkID, from, to, origin, time.Duration(slot)*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get click time series: %w", err)
+	}
+	for _, count := range counts {
+		slotStart := origin.Add(time.Duration(count.Slot*slot) * time.Second)
+		if i, ok := index[truncateToInterval(slotStart, interval, loc).Unix()]; ok {
+			buckets[i].Clicks += count.Clicks
+		}
+	}
+	return buckets, nil
+}
+
+// CountUniqueVisitors retourne l'estimation du nombre de visiteurs uniques d'un lien.
+// Un même visiteur (IP + User-Agent) est compté au plus une fois par jour.
+func (s *ClickService) CountUniqueVisitors(linkID uint) (uint64, error) {
+	sketch, err := s.clickRepo.GetVisitorSketch(linkID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unique visitors: %w", err)
+	}
+	return sketch.Count(), nil
+}
+
+// GetClickBreakdowns retourne les top N referrers, navigateurs, systèmes et appareils d'un lien.
+// Les valeurs vides sont libellées "(direct)" pour les referrers et "(unknown)" sinon.
+func (s *ClickService) GetClickBreakdowns(linkID uint, top int) (*ClickBreakdowns, error) {
+	breakdowns := &ClickBreakdowns{}
+	targets := []struct {
+		dimension string
+		dest      *[]repository.BreakdownEntry
+		emptyName string
+	}{
+		{repository.DimensionReferrer, &breakdowns.Referrers, "(direct)"},
+		{repository.DimensionBrowser, &breakdowns.Browsers, "(unknown)"},
+		{repository.DimensionOS, &breakdowns.OperatingSystems, "(unknown)"},
+		{repository.DimensionDevice, &breakdowns.Devices, "(unknown)"},
+	}
+
+	for _, target := range targets {
+		entries, err := s.clickRepo.GetClickBreakdown(linkID, target.dimension, top)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get click breakdowns: %w", err)
+		}
+		for i := range entries {
+			if entries[i].Value == "" {
+				entries[i].Value = target.emptyName
+			}
+		}
+		if entries == nil {
+			entries = []repository.BreakdownEntry{}
+		}
+		*target.dest = entries
+	}
+	return breakdowns, nil
+}
+
+// DefaultTimeSeriesSpan retourne la durée couverte par défaut quand 'from' n'est pas précisé :
+// 24 heures par heure, 30 jours par jour et 12 semaines par semaine.
+func DefaultTimeSeriesSpan(interval string) time.Duration {
+	switch interval {
+	case IntervalHour:
+		return 24 * time.Hour
+	case IntervalWeek:
+		return 12 * 7 * 24 * time.Hour
+	default:
+		return 30 * 24 * time.Hour
+	}
+}
+
+// gcd retourne le plus grand commun diviseur de a et b (b si a est nul).
+func gcd(a, b int64) int64 {
+	for a != 0 {
+		a, b = b%a, a
+	}
+	return b
+}
+
+// truncateToInterval retourne le début de la période contenant t dans le fuseau loc.
+func truncateToInterval(t time.Time, interval string, loc *time.Location) time.Time {
+	t = t.In(loc)
+	switch interval {
+	case IntervalHour:
+		// Retrancher minutes et secondes conserve le bon décalage lors des changements d'heure.
+		return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case IntervalWeek:
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+}
+
+// nextInterval retourne le début de la période qui suit celle commençant à start.
+func nextInterval(start time.Time, interval string, loc *time.Location) time.Time {
+	switch interval {
+	case IntervalHour:
+		return start.Add(time.Hour)
+	case IntervalWeek:
+		return time.Date(start.Year(), start.Month(), start.Day()+7, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, loc)
+	}
+}
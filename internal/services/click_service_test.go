@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // Fuseaux horaires disponibles même sans base tz sur la machine
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+)
+
+func TestGetClickTimeSeriesBucketsInTimeZone(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		interval string
+		loc      *time.Location
+		from, to time.Time
+		clicks   []time.Time
+		want     []int
+	}{
+		{
+			// Le 29 mars 2026 ne dure que 23 heures à Paris (passage à l'heure d'été).
+			name:     "day across DST change",
+			interval: IntervalDay,
+			loc:      paris,
+			from:     time.Date(2026, 3, 28, 0, 0, 0, 0, paris),
+			to:       time.Date(2026, 3, 31, 0, 0, 0, 0, paris),
+			clicks: []time.Time{
+				time.Date(2026, 3, 28, 23, 59, 0, 0, paris),
+				time.Date(2026, 3, 29, 0, 1, 0, 0, paris),
+				time.Date(2026, 3, 29, 23, 59, 0, 0, paris),
+				time.Date(2026, 3, 30, 0, 0, 0, 0, paris),
+			},
+			want: []int{1, 2, 1},
+		},
+		{
+			// Les heures de Kolkata (UTC+5:30) commencent à la demi-heure UTC.
+			name:     "hour with half-hour offset",
+			interval: IntervalHour,
+			loc:      kolkata,
+			from:     time.Date(2026, 5, 1, 10, 0, 0, 0, kolkata),
+			to:       time.Date(2026, 5, 1, 12, 0, 0, 0, kolkata),
+			clicks: []time.Time{
+				time.Date(2026, 5, 1, 10, 59, 59, 0, kolkata),
+				time.Date(2026, 5, 1, 11, 0, 0, 0, kolkata),
+				time.Date(2026, 5, 1, 12, 0, 0, 0, kolkata),
+			},
+			want: []int{1, 1},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := repository.NewMemoryStore()
+			link := &models.Link{ShortCode: "series", LongURL: "https://example.com"}
+			if err := repository.NewMemoryLinkRepository(store).CreateLink(link); err != nil {
+				t.Fatalf("create link: %v", err)
+			}
+			clickRepo := repository.NewMemoryClickRepository(store)
+			for _, ts := range tc.clicks {
+				if err := clickRepo.CreateClick(&models.Click{LinkID: link.ID, Timestamp: ts}); err != nil {
+					t.Fatalf("create click: %v", err)
+				}
+			}
+
+			buckets, err := NewClickService(clickRepo).GetClickTimeSeries(link.ID, tc.from, tc.to, tc.interval, tc.loc)
+			if err != nil {
+				t.Fatalf("get time series: %v", err)
+			}
+			got := make([]int, len(buckets))
+			for i, bucket := range buckets {
+				got[i] = bucket.Clicks
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got clicks %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("got clicks %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+)
+
+// États de santé d'un lien, d'après sa dernière vérification et le seuil d'échecs du moniteur.
+const (
+	HealthUp      = models.LinkStateUp
+	HealthDown    = models.LinkStateDown
+	HealthUnknown = "unknown" // Jamais vérifié
+)
+
+// Bornes des paramètres de GetLinkHealth.
+const (
+	DefaultHealthWindow  = 24 * time.Hour
+	MaxHealthWindow      = 90 * 24 * time.Hour
+	DefaultHealthHistory = 20
+	MaxHealthHistory     = 100
+)
+
+// ErrInvalidHealthParams est retournée quand la fenêtre ou la taille d'historique demandée est invalide.
+var ErrInvalidHealthParams = errors.New("invalid health parameters")
+
+// LinkHealth résume l'état de santé de la destination d'un lien.
+type LinkHealth struct {
+	State               string             // HealthUp, HealthDown ou HealthUnknown
+	ConsecutiveFailures int                // Échecs consécutifs lors des dernières vérifications
+	LastCheckedAt       *time.Time         // Date de la dernière vérification, nil si jamais vérifié
+	Window              time.Duration      // Fenêtre de calcul de la disponibilité
+	Checks              int64              // Nombre de vérifications dans la fenêtre
+	UptimePercent       *float64           // Part des vérifications réussies dans la fenêtre, nil sans vérification
+	Recent              []models.LinkCheck // Dernières vérifications, de la plus récente à la plus ancienne
+}
+
+// HealthService fournit l'historique de surveillance des liens.
+type HealthService struct {
+	checkRepo repository.LinkCheckRepository
+}
+
+// NewHealthService crée et retourne une nouvelle instance de HealthService.
+func NewHealthService(checkRepo repository.LinkCheckRepository) *HealthService {
+	return &HealthService{
+		checkRepo: checkRepo,
+	}
+}
+
+// GetLinkHealth calcule la disponibilité d'un lien sur window et retourne ses limit dernières vérifications.
+// Une fenêtre ou une limite nulle prend la valeur par défaut.
+func (s *HealthService) GetLinkHealth(linkID uint, window time.Duration, limit int) (*LinkHealth, error) {
+	if window == 0 {
+		window = DefaultHealthWindow
+	}
+	if limit == 0 {
+		limit = DefaultHealthHistory
+	}
+	if window < 0 || window > MaxHealthWindow {
+		return nil, fmt.Errorf("%w: window must be between 0 and %v", ErrInvalidHealthParams, MaxHealthWindow)
+	}
+	if limit < 0 || limit > MaxHealthHistory {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidHealthParams, MaxHealthHistory)
+	}
+
+	recent, err := s.checkRepo.GetRecentChecks(linkID, limit)
+	if err != nil {
+		return nil, err
+	}
+	total, up, err := s.checkRepo.CountChecksSince(linkID, time.Now().Add(-window))
+	if err != nil {
+		return nil, err
+	}
+
+	health := &LinkHealth{
+		State:  HealthUnknown,
+		Window: window,
+		Checks: total,
+		Recent: recent,
+	}
+	if len(recent) > 0 {
+		last := recent[0]
+		health.LastCheckedAt = &last.CheckedAt
+		health.ConsecutiveFailures = last.ConsecutiveFailures
+		health.State = HealthDown
+		if last.LinkUp() {
+			health.State = HealthUp
+		}
+	}
+	if total > 0 {
+		uptime := float64(up) / float64(total) * 100
+		health.UptimePercent = &uptime
+	}
+	return health, nil
+}
@@ -2,35 +2,206 @@ package services
 
 import (
 	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
-
-	"gorm.io/gorm" // Nécessaire pour la gestion spécifique de gorm.ErrRecordNotFound
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/axellelanca/urlshortener/internal/models"
 	"github.com/axellelanca/urlshortener/internal/repository" // Importe le package repository
+	"github.com/axellelanca/urlshortener/internal/urlsafety"
 )
 
 // Définition du jeu de caractères pour la génération des codes courts.
 const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 
+// Contraintes appliquées aux alias personnalisés.
+const (
+	aliasCharset   = charset + "-_"
+	aliasMinLength = 3
+	aliasMaxLength = 32
+)
+
+// reservedAliases liste les mots qui ne peuvent pas être utilisés comme alias
+// car ils entrent en conflit avec des routes du service (comparaison insensible à la casse).
+var reservedAliases = map[string]struct{}{
+	"api":     {},
+	"health":  {},
+	"metrics": {},
+	"admin":   {},
+	"static":  {},
+}
+
+// Erreurs métier retournées lors de la création d'un lien avec un alias personnalisé.
+var (
+	ErrInvalidAlias  = errors.New("invalid custom alias")
+	ErrReservedAlias = errors.New("custom alias is reserved")
+	ErrAliasTaken    = errors.New("custom alias is already taken")
+)
+
+// Erreurs métier liées à la durée de vie d'un lien.
+var (
+	ErrInvalidExpiration    = errors.New("invalid expiration settings")
+	ErrLinkExpired          = errors.New("link has expired")
+	ErrClickBudgetExhausted = errors.New("link click budget is exhausted")
+	ErrLinkDisabled         = errors.New("link is disabled")
+	ErrLinkUnreachable      = errors.New("link destination is unreachable")
+)
+
+// ErrInvalidRedirectType est retournée quand le code de redirection demandé n'est ni 301, 302, 307 ni 308.
+var ErrInvalidRedirectType = errors.New("invalid redirect type")
+
+// ErrUnsafeURL est retournée quand l'URL de destination est refusée par la validation de sécurité.
+var ErrUnsafeURL = urlsafety.ErrUnsafeURL
+
+// CreateLinkOptions regroupe les paramètres optionnels de création d'un lien.
+type CreateLinkOptions struct {
+	CustomAlias  string     // Alias choisi par l'utilisateur, vide pour un code aléatoire
+	ExpiresAt    *time.Time // Date d'expiration optionnelle
+	MaxClicks    int        // Nombre maximal de redirections (0 = illimité)
+	FallbackURL  string     // URL de repli quand la destination est durablement inaccessible (optionnelle)
+	RedirectType int        // Code HTTP de la redirection (0 = type par défaut du service)
+}
+
+// Bornes de pagination de la liste des liens.
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// ErrInvalidListParams est retournée quand les paramètres de listing sont incohérents.
+var ErrInvalidListParams = errors.New("invalid list parameters")
+
+// ListLinksParams regroupe les paramètres de listing des liens exposés à l'API et à la CLI.
+type ListLinksParams struct {
+	Domain      string     // Domaine de destination
+	CreatedFrom *time.Time // Date de création minimale (incluse)
+	CreatedTo   *time.Time // Date de création maximale (exclue)
+	Status      string     // "active", "disabled", "expired" ou vide
+	SortBy      string     // "created_at" (défaut) ou "clicks"
+	Order       string     // "desc" (défaut) ou "asc"
+	Cursor      string     // Curseur opaque retourné par la page précédente
+	Limit       int        // Taille de page (défaut DefaultListLimit, max MaxListLimit)
+}
+
+// LinkPage est une page de résultats du listing des liens.
+type LinkPage struct {
+	Links      []repository.LinkSummary
+	NextCursor string // Vide s'il n'y a plus de page suivante
+}
+
+// listCursor est le contenu du curseur de pagination, encodé en base64 pour rester opaque.
+type listCursor struct {
+	ID     uint `json:"id"`
+	Clicks int  `json:"clicks,omitempty"`
+}
+
+// UpdateLinkOptions regroupe les modifications applicables à un lien existant.
+// Un champ nil n'est pas modifié.
+type UpdateLinkOptions struct {
+	LongURL      *string // Nouvelle URL de destination
+	Disabled     *bool   // Active ou désactive la redirection
+	FallbackURL  *string // Nouvelle URL de repli, vide pour la retirer
+	RedirectType *int    // Nouveau code HTTP de la redirection
+}
+
+// validateExpiration vérifie la cohérence des paramètres de durée de vie d'un lien.
+func validateExpiration(opts CreateLinkOptions) error {
+	if opts.ExpiresAt != nil && !opts.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("%w: expiration date must be in the future", ErrInvalidExpiration)
+	}
+	if opts.MaxClicks < 0 {
+		return fmt.Errorf("%w: max clicks must be positive", ErrInvalidExpiration)
+	}
+	return nil
+}
+
+// ValidateRedirectType vérifie qu'un code de redirection fait partie de ceux acceptés pour un lien.
+func ValidateRedirectType(code int) error {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return nil
+	}
+	return fmt.Errorf("%w %d (expected 301, 302, 307 or 308)", ErrInvalidRedirectType, code)
+}
+
+// IsPermanentRedirect indique si un code de redirection est permanent (301 ou 308) :
+// les navigateurs et les proxys peuvent alors le mettre en cache.
+func IsPermanentRedirect(code int) bool {
+	return code == http.StatusMovedPermanently || code == http.StatusPermanentRedirect
+}
+
+// PreservesRequestMethod indique si un code de redirection impose au client de conserver
+// la méthode et le corps de la requête (307 ou 308). Les autres (301, 302) sont suivis en GET.
+func PreservesRequestMethod(code int) bool {
+	return code == http.StatusTemporaryRedirect || code == http.StatusPermanentRedirect
+}
+
 // LinkService est une structure qui fournit des méthodes pour la logique métier des liens.
 // Elle détient linkRepo qui est une référence vers une interface LinkRepository.
 type LinkService struct {
-	linkRepo repository.LinkRepository // Interface pour accéder aux méthodes du repository
+	linkRepo     repository.LinkRepository // Interface pour accéder aux méthodes du repository
+	urlValidator *urlsafety.Validator      // Vérifie les URLs de destination à la création et à la modification
+	fallbackURL  string                    // URL de repli globale des liens durablement inaccessibles
+	auditActor   string                    // Auteur des modifications manuelles dans le journal d'audit
+
+	defaultRedirectType     int           // Code de redirection des liens créés sans type explicite
+	permanentRedirectMaxAge time.Duration // Durée de mise en cache des redirections permanentes (0 = aucune)
 }
 
 // NewLinkService crée et retourne une nouvelle instance de LinkService.
+// Par défaut, seules les URLs http(s) vers des adresses publiques sont acceptées ;
+// SetURLValidator permet d'appliquer la configuration complète (liste de blocage, etc.).
 func NewLinkService(linkRepo repository.LinkRepository) *LinkService {
 	return &LinkService{
-		linkRepo: linkRepo,
+		linkRepo:     linkRepo,
+		urlValidator: urlsafety.NewValidator(urlsafety.Options{}),
+		auditActor:   models.AuditActorAPI,
+
+		defaultRedirectType: http.StatusFound,
 	}
 }
 
+// SetURLValidator remplace la validation appliquée aux URLs de destination.
+func (s *LinkService) SetURLValidator(v *urlsafety.Validator) {
+	s.urlValidator = v
+}
+
+// SetUnreachableFallbackURL définit l'URL de repli utilisée pour les liens basculés en mode
+// repli par le moniteur qui n'ont pas leur propre URL de repli.
+func (s *LinkService) SetUnreachableFallbackURL(url string) {
+	s.fallbackURL = url
+}
+
+// SetAuditActor définit l'auteur (models.AuditActor*) enregistré dans le journal d'audit
+// pour les modifications et suppressions de liens (models.AuditActorAPI par défaut).
+func (s *LinkService) SetAuditActor(actor string) {
+	s.auditActor = actor
+}
+
+// SetDefaultRedirectType définit le code de redirection des liens créés sans type explicite.
+func (s *LinkService) SetDefaultRedirectType(code int) {
+	s.defaultRedirectType = code
+}
+
+// SetPermanentRedirectMaxAge définit la durée pendant laquelle les clients peuvent mettre
+// en cache une redirection permanente (0 pour l'interdire).
+func (s *LinkService) SetPermanentRedirectMaxAge(maxAge time.Duration) {
+	s.permanentRedirectMaxAge = maxAge
+}
+
 // GenerateShortCode génère un code court aléatoire d'une longueur spécifiée.
 func (s *LinkService) GenerateShortCode(length int) (string, error) {
 	// Génère un code court aléatoire sécurisé de la longueur spécifiée
+	return randomString(length)
+}
+
+// randomString génère une chaîne aléatoire sécurisée de caractères de charset.
+func randomString(length int) (string, error) {
 	b := make([]byte, length)
 	for i := range b {
 		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
@@ -42,8 +213,100 @@ func (s *LinkService) GenerateShortCode(length int) (string, error) {
 	return string(b), nil
 }
 
+// ValidateAlias vérifie qu'un alias personnalisé respecte le jeu de caractères,
+// la longueur autorisée et qu'il ne fait pas partie des mots réservés.
+func ValidateAlias(alias string) error {
+	if len(alias) < aliasMinLength || len(alias) > aliasMaxLength {
+		return fmt.Errorf("%w: length must be between %d and %d characters", ErrInvalidAlias, aliasMinLength, aliasMaxLength)
+	}
+	for _, r := range alias {
+		if !strings.ContainsRune(aliasCharset, r) {
+			return fmt.Errorf("%w: character %q is not allowed", ErrInvalidAlias, r)
+		}
+	}
+	if _, reserved := reservedAliases[strings.ToLower(alias)]; reserved {
+		return fmt.Errorf("%w: %q", ErrReservedAlias, alias)
+	}
+	return nil
+}
+
 // CreateLink crée un nouveau lien raccourci.
-func (s *LinkService) CreateLink(longURL string) (*models.Link, error) {
+// Si opts.CustomAlias est renseigné, il est utilisé comme code court à la place d'un code aléatoire.
+func (s *LinkService) CreateLink(longURL string, opts CreateLinkOptions) (*models.Link, error) {
+	if err := s.urlValidator.Validate(longURL); err != nil {
+		return nil, err
+	}
+	if err := validateExpiration(opts); err != nil {
+		return nil, err
+	}
+	if opts.FallbackURL != "" {
+		if err := s.urlValidator.Validate(opts.FallbackURL); err != nil {
+			return nil, fmt.Errorf("fallback url: %w", err)
+		}
+	}
+	redirectType := opts.RedirectType
+	if redirectType == 0 {
+		redirectType = s.defaultRedirectType
+	}
+	if err := ValidateRedirectType(redirectType); err != nil {
+		return nil, err
+	}
+
+	var shortCode string
+	var err error
+
+	if opts.CustomAlias != "" {
+		shortCode, err = s.reserveAlias(opts.CustomAlias)
+	} else {
+		shortCode, err = s.generateUniqueShortCode()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Crée une nouvelle instance du modèle Link
+	link := &models.Link{
+		ShortCode:   shortCode,
+		LongURL:     longURL,
+		ExpiresAt:   opts.ExpiresAt,
+		MaxClicks:   opts.MaxClicks,
+		FallbackURL: opts.FallbackURL,
+
+		RedirectType: redirectType,
+		// CreatedAt sera géré automatiquement par GORM
+	}
+
+	// Persiste le nouveau lien dans la base de données via le repository
+	if err := s.linkRepo.CreateLink(link); err != nil {
+		// Un autre appel a pu réserver le même alias entre la vérification et l'insertion.
+		if opts.CustomAlias != "" && repository.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("%w: %q", ErrAliasTaken, opts.CustomAlias)
+		}
+		return nil, fmt.Errorf("failed to persist new link: %w", err)
+	}
+
+	return link, nil
+}
+
+// reserveAlias valide un alias personnalisé et vérifie qu'il n'est pas déjà utilisé.
+func (s *LinkService) reserveAlias(alias string) (string, error) {
+	if err := ValidateAlias(alias); err != nil {
+		return "", err
+	}
+
+	// Les alias des liens supprimés restent réservés pour éviter toute réutilisation.
+	exists, err := s.linkRepo.ShortCodeExists(alias)
+	if err != nil {
+		return "", fmt.Errorf("database error checking alias availability: %w", err)
+	}
+	if exists {
+		return "", fmt.Errorf("%w: %q", ErrAliasTaken, alias)
+	}
+	return alias, nil
+}
+
+// generateUniqueShortCode génère un code court aléatoire qui n'existe pas encore en base.
+func (s *LinkService) generateUniqueShortCode() (string, error) {
 	const codeLength = 6
 	const maxRetries = 5
 	var shortCode string
@@ -54,40 +317,26 @@ func (s *LinkService) CreateLink(longURL string) (*models.Link, error) {
 		var code string
 		code, err = s.GenerateShortCode(codeLength)
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate short code: %w", err)
+			return "", fmt.Errorf("failed to generate short code: %w", err)
 		}
 
-		// Vérifie si le code existe déjà
-		_, err = s.linkRepo.GetLinkByShortCode(code)
+		// Vérifie si le code existe déjà (y compris parmi les liens supprimés)
+		var exists bool
+		exists, err = s.linkRepo.ShortCodeExists(code)
 		if err != nil {
-			// Si l'erreur est 'record not found', le code est unique
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				shortCode = code
-				break
-			}
-			// Autre erreur DB
-			return nil, fmt.Errorf("database error checking short code uniqueness: %w", err)
+			return "", fmt.Errorf("database error checking short code uniqueness: %w", err)
+		}
+		if !exists {
+			shortCode = code
+			break
 		}
-		// Si aucune erreur, le code existe déjà, on retente
+		// Le code existe déjà, on retente
 	}
 
 	if shortCode == "" {
-		return nil, errors.New("could not generate a unique short code after several attempts")
-	}
-
-	// Crée une nouvelle instance du modèle Link
-	link := &models.Link{
-		ShortCode: shortCode,
-		LongURL:   longURL,
-		// CreatedAt sera géré automatiquement par GORM
-	}
-
-	// Persiste le nouveau lien dans la base de données via le repository
-	if err := s.linkRepo.CreateLink(link); err != nil {
-		return nil, fmt.Errorf("failed to persist new link: %w", err)
+		return "", errors.New("could not generate a unique short code after several attempts")
 	}
-
-	return link, nil
+	return shortCode, nil
 }
 
 // GetLinkByShortCode récupère un lien via son code court.
@@ -115,4 +364,260 @@ func (s *LinkService) GetLinkStats(shortCode string) (*models.Link, int, error)
 	}
 
 	return link, clicks, nil
-}
\ No newline at end of file
+}
+
+// UpdateLink modifie la destination, l'URL de repli, le type de redirection et/ou l'état d'activation d'un lien existant.
+// La modification est enregistrée dans le journal d'audit du lien avec l'auteur défini par SetAuditActor.
+func (s *LinkService) UpdateLink(shortCode string, opts UpdateLinkOptions) (*models.Link, error) {
+	if opts.LongURL != nil {
+		if err := s.urlValidator.Validate(*opts.LongURL); err != nil {
+			return nil, err
+		}
+	}
+	if opts.FallbackURL != nil && *opts.FallbackURL != "" {
+		if err := s.urlValidator.Validate(*opts.FallbackURL); err != nil {
+			return nil, fmt.Errorf("fallback url: %w", err)
+		}
+	}
+	if opts.RedirectType != nil {
+		if err := ValidateRedirectType(*opts.RedirectType); err != nil {
+			return nil, err
+		}
+	}
+
+	link, err := s.linkRepo.GetLinkByShortCode(shortCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link by shortcode: %w", err)
+	}
+
+	// Seuls les champs demandés sont écrits : le lien lu (éventuellement depuis le cache)
+	// peut être en retard sur l'expiration ou l'état de santé posés entre-temps.
+	var fields, changes []string
+	if opts.LongURL != nil {
+		link.LongURL = *opts.LongURL
+		fields = append(fields, "LongURL")
+		changes = append(changes, "destination: "+link.LongURL)
+	}
+	if opts.FallbackURL != nil {
+		link.FallbackURL = *opts.FallbackURL
+		fields = append(fields, "FallbackURL")
+		changes = append(changes, "URL de repli: "+link.FallbackURL)
+	}
+	if opts.RedirectType != nil {
+		link.RedirectType = *opts.RedirectType
+		fields = append(fields, "RedirectType")
+		changes = append(changes, fmt.Sprintf("type de redirection: %d", link.RedirectType))
+	}
+	action := models.AuditLinkUpdated
+	if opts.Disabled != nil {
+		link.Disabled = *opts.Disabled
+		fields = append(fields, "Disabled")
+		if len(changes) == 0 {
+			// Une simple (dés)activation a sa propre action dans le journal.
+			action = models.AuditLinkEnabled
+			if link.Disabled {
+				action = models.AuditLinkDisabled
+			}
+		} else {
+			changes = append(changes, fmt.Sprintf("désactivé: %t", link.Disabled))
+		}
+	}
+	if len(fields) == 0 {
+		return link, nil
+	}
+
+	event := &models.LinkAuditEvent{
+		LinkID: link.ID,
+		Actor:  s.auditActor,
+		Action: action,
+		Detail: truncate(strings.Join(changes, ", "), 512),
+	}
+	if err := s.linkRepo.UpdateLink(link, event, fields...); err != nil {
+		return nil, fmt.Errorf("failed to update link: %w", err)
+	}
+	// Relit le lien pour retourner aussi les colonnes modifiées par ailleurs.
+	updated, err := s.linkRepo.GetLinkByShortCode(shortCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updated link: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteLink supprime logiquement un lien : il ne redirige plus et n'apparaît plus
+// dans les recherches, mais son code court reste réservé.
+// La suppression est enregistrée dans le journal d'audit du lien.
+func (s *LinkService) DeleteLink(shortCode string) error {
+	link, err := s.linkRepo.GetLinkByShortCode(shortCode)
+	if err != nil {
+		return fmt.Errorf("failed to get link by shortcode: %w", err)
+	}
+	event := &models.LinkAuditEvent{LinkID: link.ID, Actor: s.auditActor, Action: models.AuditLinkDeleted}
+	if err := s.linkRepo.DeleteLink(link, event); err != nil {
+		return fmt.Errorf("failed to delete link: %w", err)
+	}
+	return nil
+}
+
+// ListLinks retourne une page de liens filtrée et triée, ainsi que le curseur de la page suivante.
+func (s *LinkService) ListLinks(params ListLinksParams) (*LinkPage, error) {
+	filter := repository.LinkFilter{
+		Domain:      params.Domain,
+		CreatedFrom: params.CreatedFrom,
+		CreatedTo:   params.CreatedTo,
+		Status:      params.Status,
+		SortBy:      params.SortBy,
+		Limit:       params.Limit,
+	}
+
+	if filter.SortBy == "" {
+		filter.SortBy = repository.SortByCreatedAt
+	}
+	if filter.SortBy != repository.SortByCreatedAt && filter.SortBy != repository.SortByClicks {
+		return nil, fmt.Errorf("%w: unknown sort %q", ErrInvalidListParams, params.SortBy)
+	}
+
+	switch params.Order {
+	case "", "desc":
+		filter.Descending = true
+	case "asc":
+	default:
+		return nil, fmt.Errorf("%w: unknown order %q", ErrInvalidListParams, params.Order)
+	}
+
+	switch filter.Status {
+	case "", repository.StatusActive, repository.StatusDisabled, repository.StatusExpired:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidListParams, params.Status)
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultListLimit
+	}
+	filter.Limit = min(filter.Limit, MaxListLimit)
+
+	if params.Cursor != "" {
+		cursor, err := decodeListCursor(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.AfterID = cursor.ID
+		filter.AfterClicks = cursor.Clicks
+	}
+
+	// On demande un élément de plus pour savoir s'il existe une page suivante.
+	filter.Limit++
+	links, err := s.linkRepo.ListLinks(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list links: %w", err)
+	}
+
+	page := &LinkPage{Links: links}
+	if len(links) == filter.Limit {
+		page.Links = links[:len(links)-1]
+		last := page.Links[len(page.Links)-1]
+		page.NextCursor = encodeListCursor(listCursor{ID: last.ID, Clicks: last.ClickCount})
+	}
+	return page, nil
+}
+
+// encodeListCursor sérialise un curseur de pagination.
+func encodeListCursor(cursor listCursor) string {
+	raw, _ := json.Marshal(cursor) // Ne peut pas échouer pour cette structure
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeListCursor désérialise un curseur de pagination reçu d'un client.
+func decodeListCursor(encoded string) (listCursor, error) {
+	var cursor listCursor
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || json.Unmarshal(raw, &cursor) != nil || cursor.ID == 0 {
+		return cursor, fmt.Errorf("%w: malformed cursor", ErrInvalidListParams)
+	}
+	return cursor, nil
+}
+
+// CheckLinkAvailability vérifie qu'un lien peut encore être utilisé pour une redirection.
+// Elle retourne ErrLinkDisabled si le lien a été désactivé, ErrLinkExpired si sa date
+// d'expiration est dépassée et ErrLinkUnreachable si le moniteur l'a suspendu faute de
+// destination accessible. Le budget de clics est vérifié ensuite par ReserveClick.
+func (s *LinkService) CheckLinkAvailability(link *models.Link) error {
+	if link.Disabled {
+		return ErrLinkDisabled
+	}
+	// Un lien déjà marqué par le sweeper est refusé sans requête supplémentaire.
+	if link.Expired || link.IsExpiredAt(time.Now()) {
+		return ErrLinkExpired
+	}
+	switch link.HealthAction {
+	case models.HealthActionDisable:
+		return ErrLinkUnreachable
+	case models.HealthActionFallback:
+		// Sans URL de repli (retirée depuis la bascule), le lien est traité comme suspendu.
+		if s.DestinationURL(link) == "" {
+			return ErrLinkUnreachable
+		}
+	}
+	return nil
+}
+
+// ReserveClick décompte la redirection en cours du budget de clics du lien, s'il en a un.
+// La réservation est atomique en base : des redirections simultanées ne peuvent pas dépasser
+// le budget, même si les clics ne sont enregistrés que plus tard. Elle retourne
+// ErrClickBudgetExhausted si le budget est déjà atteint.
+func (s *LinkService) ReserveClick(link *models.Link) error {
+	if link.MaxClicks == 0 {
+		return nil
+	}
+	reserved, err := s.linkRepo.ReserveClick(link.ID)
+	if err != nil {
+		return fmt.Errorf("failed to reserve click for link: %w", err)
+	}
+	if !reserved {
+		return ErrClickBudgetExhausted
+	}
+	return nil
+}
+
+// DestinationURL retourne l'URL vers laquelle rediriger : l'URL longue, ou l'URL de repli
+// (celle du lien, à défaut l'URL globale) quand le moniteur a basculé le lien en mode repli.
+func (s *LinkService) DestinationURL(link *models.Link) string {
+	if link.HealthAction != models.HealthActionFallback {
+		return link.LongURL
+	}
+	if link.FallbackURL != "" {
+		return link.FallbackURL
+	}
+	return s.fallbackURL
+}
+
+// RedirectStatus retourne le code HTTP de la redirection d'un lien, ou le type par défaut
+// si le lien n'en a pas de valide.
+func (s *LinkService) RedirectStatus(link *models.Link) int {
+	if ValidateRedirectType(link.RedirectType) != nil {
+		return s.defaultRedirectType
+	}
+	return link.RedirectType
+}
+
+// RedirectCacheMaxAge retourne la durée pendant laquelle les clients peuvent mettre en cache
+// la redirection d'un lien, 0 s'ils doivent revenir à chaque clic. Seules les redirections
+// permanentes sont mises en cache, jamais au-delà de l'expiration du lien. Un lien à budget
+// de clics ou basculé par le moniteur n'est pas mis en cache : chaque clic doit être vérifié.
+func (s *LinkService) RedirectCacheMaxAge(link *models.Link, now time.Time) time.Duration {
+	if !IsPermanentRedirect(s.RedirectStatus(link)) || link.MaxClicks > 0 || link.HealthAction != "" {
+		return 0
+	}
+	maxAge := s.permanentRedirectMaxAge
+	if link.ExpiresAt != nil {
+		maxAge = min(maxAge, link.ExpiresAt.Sub(now))
+	}
+	return max(maxAge, 0)
+}
+
+// truncate limite s à max octets pour respecter la taille des colonnes.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return strings.ToValidUTF8(s[:max], "")
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/axellelanca/urlshortener/internal/migrations"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// racingLinkRepository simule une requête concurrente : la vérification de disponibilité
+// de l'alias passe, mais un autre appel l'a inséré avant l'insertion.
+type racingLinkRepository struct {
+	repository.LinkRepository
+}
+
+func (racingLinkRepository) ShortCodeExists(string) (bool, error) {
+	return false, nil
+}
+
+// openTestDatabase ouvre une base SQLite temporaire migrée.
+func openTestDatabase(t *testing.T, translateError bool) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "links.db")), &gorm.Config{
+		Logger:         logger.Discard,
+		TranslateError: translateError,
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("get sql database: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if _, err := migrations.NewMigrator(db).Up(); err != nil {
+		t.Fatalf("migrate database: %v", err)
+	}
+	return db
+}
+
+func TestCreateLinkDuplicateAliasReturnsAliasTaken(t *testing.T) {
+	// La contrainte d'unicité doit être reconnue que l'erreur soit traduite par GORM ou non.
+	for _, translateError := range []bool{true, false} {
+		db := openTestDatabase(t, translateError)
+		if err := db.Create(&models.Link{ShortCode: "spring-sale", LongURL: "https://example.com/a"}).Error; err != nil {
+			t.Fatalf("insert link: %v", err)
+		}
+
+		service := NewLinkService(racingLinkRepository{repository.NewLinkRepository(db)})
+		_, err := service.CreateLink("https://example.com/b", CreateLinkOptions{CustomAlias: "spring-sale"})
+		if !errors.Is(err, ErrAliasTaken) {
+			t.Errorf("translate_error=%v: CreateLink error = %v, want ErrAliasTaken", translateError, err)
+		}
+	}
+}
+
+func TestManualChangesAreAudited(t *testing.T) {
+	db := openTestDatabase(t, true)
+	service := NewLinkService(repository.NewLinkRepository(db))
+	service.SetAuditActor(models.AuditActorCLI)
+	link, err := service.CreateLink("https://example.com", CreateLinkOptions{CustomAlias: "audited"})
+	if err != nil {
+		t.Fatalf("create link: %v", err)
+	}
+
+	disabled := true
+	if _, err := service.UpdateLink("audited", UpdateLinkOptions{Disabled: &disabled}); err != nil {
+		t.Fatalf("disable link: %v", err)
+	}
+	longURL := "https://example.org"
+	if _, err := service.UpdateLink("audited", UpdateLinkOptions{LongURL: &longURL}); err != nil {
+		t.Fatalf("update link: %v", err)
+	}
+	if _, err := service.UpdateLink("audited", UpdateLinkOptions{}); err != nil { // Aucun changement : pas d'entrée
+		t.Fatalf("empty update: %v", err)
+	}
+	if err := service.DeleteLink("audited"); err != nil {
+		t.Fatalf("delete link: %v", err)
+	}
+
+	events, err := repository.NewLinkAuditRepository(db).GetAuditEvents(link.ID, 10)
+	if err != nil {
+		t.Fatalf("get audit events: %v", err)
+	}
+	want := []string{models.AuditLinkDeleted, models.AuditLinkUpdated, models.AuditLinkDisabled}
+	if len(events) != len(want) {
+		t.Fatalf("got %d audit events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, event := range events {
+		if event.Action != want[i] || event.Actor != models.AuditActorCLI {
+			t.Fatalf("event %d = %s by %s, want %s by %s", i, event.Action, event.Actor, want[i], models.AuditActorCLI)
+		}
+	}
+	if events[1].Detail != "destination: https://example.org" {
+		t.Fatalf("update detail = %q", events[1].Detail)
+	}
+}
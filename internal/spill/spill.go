@@ -0,0 +1,311 @@
+package spill
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Nommage des segments : spill-<numéro sur 16 chiffres>.log, triables par ordre lexicographique.
+// La partie illisible d'un segment est conservée à côté, avec le suffixe .corrupt.
+const (
+	segmentPrefix = "spill-"
+	segmentSuffix = ".log"
+	corruptSuffix = ".corrupt"
+)
+
+// headerSize est la taille de l'en-tête d'un enregistrement : longueur puis CRC32 du contenu.
+const headerSize = 8
+
+// maxRecordSize protège la lecture contre un en-tête corrompu annonçant une taille absurde.
+const maxRecordSize = 1 << 20
+
+// ErrClosed est retournée par Append après la fermeture du journal.
+var ErrClosed = errors.New("spill log is closed")
+
+// Log est un journal append-only découpé en segments, utilisé pour mettre de côté
+// sur disque des enregistrements qui ne peuvent pas être traités immédiatement.
+//
+// Les écritures vont dans le segment actif ; Seal le ferme pour le rendre relisible
+// et les écritures suivantes ouvrent un nouveau segment. Chaque enregistrement est
+// précédé de sa longueur et de son CRC32 : une fin de segment tronquée par un arrêt
+// brutal est détectée à la relecture et conservée à part dans un fichier .corrupt.
+type Log struct {
+	dir             string
+	maxSegmentBytes int64
+
+	mu         sync.Mutex
+	active     *os.File // Segment en cours d'écriture, nil tant qu'aucun Append n'a eu lieu
+	activeSeq  uint64   // Numéro du segment actif (ou du dernier segment créé)
+	activeSize int64    // Taille courante du segment actif
+	closed     bool
+}
+
+// Open ouvre (et crée si besoin) un journal dans dir. Les segments laissés par une
+// exécution précédente sont considérés comme scellés et prêts à être relus.
+// maxSegmentBytes déclenche le passage à un nouveau segment une fois dépassé.
+func Open(dir string, maxSegmentBytes int64) (*Log, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create spill directory: %w", err)
+	}
+
+	l := &Log{dir: dir, maxSegmentBytes: maxSegmentBytes}
+	segments, err := l.listSegments()
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) > 0 {
+		l.activeSeq = segments[len(segments)-1].seq
+	}
+	return l, nil
+}
+
+// Append ajoute un enregistrement à la fin du segment actif.
+// Les données sont écrites directement dans le fichier (sans tampon applicatif) : elles
+// survivent à un arrêt du processus ; la synchronisation disque a lieu au scellement.
+func (l *Log) Append(payload []byte) error {
+	if len(payload) > maxRecordSize {
+		return fmt.Errorf("spill record of %d bytes exceeds the %d bytes limit", len(payload), maxRecordSize)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+	if l.active != nil && l.activeSize >= l.maxSegmentBytes {
+		if err := l.sealLocked(); err != nil {
+			return err
+		}
+	}
+	if l.active == nil {
+		l.activeSeq++
+		f, err := os.OpenFile(l.segmentPath(l.activeSeq), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open spill segment: %w", err)
+		}
+		l.active = f
+		l.activeSize = 0
+	}
+
+	record := make([]byte, headerSize+len(payload))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload))
+	copy(record[headerSize:], payload)
+
+	offset := l.activeSize
+	n, err := l.active.Write(record)
+	l.activeSize += int64(n)
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to append spill record: %w", err), l.rollbackLocked(offset))
+	}
+	return nil
+}
+
+// rollbackLocked retire l'écriture partielle d'un enregistrement en tronquant le segment actif
+// à offset, pour que les enregistrements suivants ne soient pas masqués par un enregistrement
+// déchiré. Si la troncature échoue, le segment est scellé : l'enregistrement déchiré reste en
+// fin de segment et les écritures suivantes vont dans un nouveau segment. l.mu doit être détenu.
+func (l *Log) rollbackLocked(offset int64) error {
+	if l.activeSize == offset {
+		return nil
+	}
+	if err := l.active.Truncate(offset); err != nil {
+		return errors.Join(fmt.Errorf("failed to roll back torn spill record: %w", err), l.sealLocked())
+	}
+	l.activeSize = offset
+	return nil
+}
+
+// Seal ferme le segment actif pour qu'il soit retourné par Segments.
+// Sans effet si aucun enregistrement n'a été écrit depuis le dernier scellement.
+func (l *Log) Seal() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.sealLocked()
+}
+
+// sealLocked ferme le segment actif ; l.mu doit être détenu.
+func (l *Log) sealLocked() error {
+	if l.active == nil {
+		return nil
+	}
+	syncErr := l.active.Sync()
+	closeErr := l.active.Close()
+	l.active = nil
+	if err := errors.Join(syncErr, closeErr); err != nil {
+		return fmt.Errorf("failed to seal spill segment: %w", err)
+	}
+	return nil
+}
+
+// Segments retourne les chemins des segments scellés, du plus ancien au plus récent.
+func (l *Log) Segments() ([]string, error) {
+	segments, err := l.listSegments()
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	activeSeq, hasActive := l.activeSeq, l.active != nil
+	l.mu.Unlock()
+
+	paths := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if hasActive && segment.seq == activeSeq {
+			continue
+		}
+		paths = append(paths, segment.path)
+	}
+	return paths, nil
+}
+
+// Remove supprime un segment scellé une fois ses enregistrements traités.
+// Si la relecture s'est arrêtée avant la fin du segment, la partie illisible est d'abord
+// déplacée dans un fichier <segment>.corrupt pour pouvoir être examinée.
+func (l *Log) Remove(path string, read SegmentRead) error {
+	if read.Corrupt() {
+		if err := saveCorruptTail(path, read.ValidBytes); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove spill segment: %w", err)
+	}
+	return nil
+}
+
+// saveCorruptTail copie la fin d'un segment, à partir de offset, dans <segment>.corrupt.
+func saveCorruptTail(path string, offset int64) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open spill segment: %w", err)
+	}
+	defer src.Close()
+	if _, err := src.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek spill segment: %w", err)
+	}
+
+	dst, err := os.OpenFile(path+corruptSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create corrupt spill file: %w", err)
+	}
+	_, copyErr := io.Copy(dst, src)
+	syncErr := dst.Sync()
+	closeErr := dst.Close()
+	if err := errors.Join(copyErr, syncErr, closeErr); err != nil {
+		return fmt.Errorf("failed to save corrupt spill tail: %w", err)
+	}
+	return nil
+}
+
+// Close scelle le segment actif et refuse les écritures suivantes.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	return l.sealLocked()
+}
+
+// SegmentRead décrit le résultat de la relecture d'un segment.
+type SegmentRead struct {
+	Records    int   // Nombre d'enregistrements valides lus
+	ValidBytes int64 // Taille du préfixe valide du segment
+	Size       int64 // Taille totale du segment
+}
+
+// Corrupt indique si le segment contient, après son préfixe valide, des octets illisibles
+// (enregistrement tronqué par un arrêt brutal ou corrompu).
+func (r SegmentRead) Corrupt() bool {
+	return r.ValidBytes < r.Size
+}
+
+// ReadSegment appelle fn pour chaque enregistrement valide d'un segment, dans l'ordre d'écriture.
+// La lecture s'arrête sans erreur au premier enregistrement tronqué ou corrompu : le résultat
+// distingue alors le préfixe valide (ValidBytes) de la taille totale du segment.
+func ReadSegment(path string, fn func(payload []byte) error) (SegmentRead, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return SegmentRead{}, fmt.Errorf("failed to open spill segment: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return SegmentRead{}, fmt.Errorf("failed to stat spill segment: %w", err)
+	}
+	read := SegmentRead{Size: info.Size()}
+
+	reader := bufio.NewReader(f)
+	header := make([]byte, headerSize)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return read, nil
+			}
+			return read, fmt.Errorf("failed to read spill segment: %w", err)
+		}
+
+		size := binary.BigEndian.Uint32(header[0:4])
+		if size > maxRecordSize {
+			return read, nil // En-tête corrompu
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			return read, nil // Enregistrement tronqué par un arrêt brutal
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+			return read, nil
+		}
+
+		if err := fn(payload); err != nil {
+			return read, err
+		}
+		read.Records++
+		read.ValidBytes += int64(headerSize) + int64(size)
+	}
+}
+
+// segmentFile décrit un segment présent sur disque.
+type segmentFile struct {
+	seq  uint64
+	path string
+}
+
+// listSegments retourne les segments du répertoire triés par numéro croissant.
+func (l *Log) listSegments() ([]segmentFile, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spill segments: %w", err)
+	}
+
+	var segments []segmentFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, segmentFile{seq: seq, path: filepath.Join(l.dir, name)})
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].seq < segments[j].seq })
+	return segments, nil
+}
+
+// segmentPath retourne le chemin du segment de numéro seq.
+func (l *Log) segmentPath(seq uint64) string {
+	return filepath.Join(l.dir, fmt.Sprintf("%s%016d%s", segmentPrefix, seq, segmentSuffix))
+}
@@ -0,0 +1,126 @@
+package spill
+
+import (
+	"encoding/binary"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// readAll relit tous les segments scellés du journal et les supprime.
+func readAll(t *testing.T, l *Log) []string {
+	t.Helper()
+	segments, err := l.Segments()
+	if err != nil {
+		t.Fatalf("list segments: %v", err)
+	}
+	var payloads []string
+	for _, path := range segments {
+		read, err := ReadSegment(path, func(payload []byte) error {
+			payloads = append(payloads, string(payload))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("read segment %s: %v", path, err)
+		}
+		if err := l.Remove(path, read); err != nil {
+			t.Fatalf("remove segment %s: %v", path, err)
+		}
+	}
+	return payloads
+}
+
+func TestReplayAfterTornTail(t *testing.T) {
+	dir := t.TempDir()
+	l, err := Open(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if err := l.Append([]byte("first")); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	// Arrêt brutal au milieu d'une écriture : l'en-tête annonce plus d'octets que présents.
+	torn := make([]byte, headerSize+3)
+	binary.BigEndian.PutUint32(torn[0:4], 100)
+	copy(torn[headerSize:], "par")
+	segment := l.segmentPath(1)
+	f, err := os.OpenFile(segment, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("open segment: %v", err)
+	}
+	if _, err := f.Write(torn); err != nil {
+		t.Fatalf("write torn tail: %v", err)
+	}
+	f.Close()
+
+	l, err = Open(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if err := l.Append([]byte("second")); err != nil {
+		t.Fatalf("append after reopen: %v", err)
+	}
+	if err := l.Seal(); err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+
+	read, err := ReadSegment(segment, func([]byte) error { return nil })
+	if err != nil {
+		t.Fatalf("read segment: %v", err)
+	}
+	want := SegmentRead{Records: 1, ValidBytes: int64(headerSize + len("first")), Size: int64(headerSize+len("first")) + int64(len(torn))}
+	if read != want || !read.Corrupt() {
+		t.Fatalf("read segment = %+v, want %+v", read, want)
+	}
+
+	if got := readAll(t, l); !reflect.DeepEqual(got, []string{"first", "second"}) {
+		t.Fatalf("replayed %q, want [first second]", got)
+	}
+
+	corrupt, err := os.ReadFile(segment + corruptSuffix)
+	if err != nil {
+		t.Fatalf("read corrupt tail: %v", err)
+	}
+	if !reflect.DeepEqual(corrupt, torn) {
+		t.Fatalf("corrupt tail = %q, want %q", corrupt, torn)
+	}
+	if remaining, err := l.Segments(); err != nil || len(remaining) != 0 {
+		t.Fatalf("segments after replay = %v, %v, want none", remaining, err)
+	}
+}
+
+func TestRollbackTornRecord(t *testing.T) {
+	l, err := Open(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer l.Close()
+	if err := l.Append([]byte("first")); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	// Écriture partielle d'un enregistrement dans le segment actif, puis retour arrière.
+	offset := l.activeSize
+	n, err := l.active.Write([]byte{0, 0, 0, 100, 1, 2})
+	if err != nil {
+		t.Fatalf("write partial record: %v", err)
+	}
+	l.activeSize += int64(n)
+	if err := l.rollbackLocked(offset); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+
+	if err := l.Append([]byte("second")); err != nil {
+		t.Fatalf("append after rollback: %v", err)
+	}
+	if err := l.Seal(); err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if got := readAll(t, l); !reflect.DeepEqual(got, []string{"first", "second"}) {
+		t.Fatalf("replayed %q, want [first second]", got)
+	}
+}
@@ -0,0 +1,158 @@
+package urlsafety
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/logging"
+)
+
+// Blocklist est une liste de domaines interdits, chargée depuis un fichier texte
+// et rechargée à chaud quand le fichier change. Un domaine bloque aussi ses sous-domaines.
+//
+// Format du fichier : un domaine par ligne, les lignes vides et les commentaires (#) sont
+// ignorés. Le format "hosts" ("0.0.0.0 domaine.com") et le préfixe "*." sont acceptés.
+type Blocklist struct {
+	path string
+
+	mu      sync.RWMutex
+	domains map[string]struct{}
+	modTime time.Time
+	size    int64
+
+	logger *slog.Logger
+}
+
+// LoadBlocklist charge la liste depuis path. Un fichier absent donne une liste vide
+// (il sera pris en compte dès sa création) ; un fichier illisible est une erreur.
+func LoadBlocklist(path string) (*Blocklist, error) {
+	b := &Blocklist{
+		path:    path,
+		domains: make(map[string]struct{}),
+		logger:  logging.Component("blocklist"),
+	}
+	if _, err := b.Reload(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Contains indique si host, ou l'un de ses domaines parents, figure dans la liste.
+func (b *Blocklist) Contains(host string) bool {
+	host = normalizeDomain(host)
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for host != "" {
+		if _, ok := b.domains[host]; ok {
+			return true
+		}
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			break
+		}
+		host = parent
+	}
+	return false
+}
+
+// Len retourne le nombre de domaines de la liste.
+func (b *Blocklist) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.domains)
+}
+
+// Reload relit le fichier s'il a changé depuis le dernier chargement.
+// Elle indique si la liste a été remplacée. En cas d'erreur, la liste précédente est conservée.
+func (b *Blocklist) Reload() (bool, error) {
+	info, err := os.Stat(b.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		changed := len(b.domains) > 0 || !b.modTime.IsZero()
+		b.domains, b.modTime, b.size = make(map[string]struct{}), time.Time{}, 0
+		return changed, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to stat blocklist %s: %w", b.path, err)
+	}
+
+	b.mu.RLock()
+	unchanged := info.ModTime().Equal(b.modTime) && info.Size() == b.size
+	b.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	f, err := os.Open(b.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open blocklist %s: %w", b.path, err)
+	}
+	defer f.Close()
+	domains, err := parseBlocklist(f)
+	if err != nil {
+		return false, fmt.Errorf("failed to read blocklist %s: %w", b.path, err)
+	}
+
+	b.mu.Lock()
+	b.domains, b.modTime, b.size = domains, info.ModTime(), info.Size()
+	b.mu.Unlock()
+	return true, nil
+}
+
+// Start surveille le fichier et le recharge à chaque modification, toutes les interval.
+// Cette fonction est conçue pour être lancée dans une goroutine séparée et se termine quand ctx est annulé.
+func (b *Blocklist) Start(ctx context.Context, interval time.Duration) {
+	b.logger.Info("Surveillance de la liste de domaines bloqués", "path", b.path, "interval", interval.String())
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := b.Reload()
+			if err != nil {
+				b.logger.Error("Échec du rechargement de la liste de domaines bloqués", "error", err)
+				continue
+			}
+			if changed {
+				b.logger.Info("Liste de domaines bloqués rechargée", "domains", b.Len())
+			}
+		}
+	}
+}
+
+// parseBlocklist lit les domaines d'une liste au format décrit sur Blocklist.
+func parseBlocklist(r io.Reader) (map[string]struct{}, error) {
+	domains := make(map[string]struct{})
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		// Format hosts : l'adresse est suivie du domaine.
+		domain := normalizeDomain(fields[len(fields)-1])
+		domain = strings.TrimPrefix(domain, "*.")
+		if domain != "" {
+			domains[domain] = struct{}{}
+		}
+	}
+	return domains, scanner.Err()
+}
+
+// normalizeDomain met un nom de domaine sous forme canonique pour la comparaison.
+func normalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
@@ -0,0 +1,169 @@
+package urlsafety
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrUnsafeURL est retournée (enveloppée avec la raison) pour toute URL de destination refusée.
+var ErrUnsafeURL = errors.New("destination url is not allowed")
+
+// DefaultSchemes liste les schémas acceptés quand aucun n'est configuré.
+var DefaultSchemes = []string{"http", "https"}
+
+// defaultResolveTimeout borne la résolution DNS d'un hôte lors de la validation.
+const defaultResolveTimeout = 2 * time.Second
+
+// Plages non routables sur Internet, en plus de celles reconnues par netip.Addr
+// (bouclage, privées, lien local, non spécifiées).
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "Ce réseau"
+	netip.MustParsePrefix("100.64.0.0/10"), // NAT de niveau opérateur
+	netip.MustParsePrefix("192.0.0.0/24"),  // Affectations IETF
+	netip.MustParsePrefix("198.18.0.0/15"), // Tests de performance
+	netip.MustParsePrefix("240.0.0.0/4"),   // Réservé, dont la diffusion
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// Options configure un Validator.
+type Options struct {
+	AllowedSchemes []string      // Schémas autorisés (DefaultSchemes si vide)
+	AllowPrivate   bool          // Accepte les cibles privées, de bouclage ou réservées
+	ResolveHosts   bool          // Résout les noms d'hôtes pour vérifier les adresses obtenues
+	ResolveTimeout time.Duration // Délai maximal de résolution DNS
+	SelfHosts      []string      // Hôtes du service lui-même, refusés pour éviter les boucles
+	Blocklist      *Blocklist    // Liste de domaines interdits, optionnelle
+}
+
+// Validator vérifie qu'une URL de destination peut être raccourcie sans danger.
+type Validator struct {
+	opts     Options
+	resolver *net.Resolver
+}
+
+// NewValidator crée un Validator à partir des options fournies.
+func NewValidator(opts Options) *Validator {
+	if len(opts.AllowedSchemes) == 0 {
+		opts.AllowedSchemes = DefaultSchemes
+	}
+	schemes := make([]string, 0, len(opts.AllowedSchemes))
+	for _, scheme := range opts.AllowedSchemes {
+		schemes = append(schemes, strings.ToLower(scheme))
+	}
+	opts.AllowedSchemes = schemes
+
+	selfHosts := make([]string, 0, len(opts.SelfHosts))
+	for _, host := range opts.SelfHosts {
+		if host = comparableHost(host); host != "" {
+			selfHosts = append(selfHosts, host)
+		}
+	}
+	opts.SelfHosts = selfHosts
+
+	if opts.ResolveTimeout <= 0 {
+		opts.ResolveTimeout = defaultResolveTimeout
+	}
+	return &Validator{opts: opts, resolver: net.DefaultResolver}
+}
+
+// Validate retourne une erreur enveloppant ErrUnsafeURL si rawURL ne doit pas être raccourcie :
+// schéma non autorisé, hôte absent, cible privée, lien vers le service lui-même
+// ou domaine présent dans la liste de blocage.
+func (v *Validator) Validate(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsafeURL, err)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if !slices.Contains(v.opts.AllowedSchemes, scheme) {
+		return fmt.Errorf("%w: scheme %q is not allowed", ErrUnsafeURL, u.Scheme)
+	}
+
+	host := normalizeDomain(u.Hostname())
+	if host == "" {
+		return fmt.Errorf("%w: missing host", ErrUnsafeURL)
+	}
+
+	if slices.Contains(v.opts.SelfHosts, comparableHost(host)) {
+		return fmt.Errorf("%w: links to this service would create a redirect loop", ErrUnsafeURL)
+	}
+
+	if v.opts.Blocklist != nil && v.opts.Blocklist.Contains(host) {
+		return fmt.Errorf("%w: domain %q is blocked", ErrUnsafeURL, host)
+	}
+
+	if !v.opts.AllowPrivate {
+		if err := v.checkPublicHost(host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPublicHost refuse les adresses IP non publiques, littérales ou obtenues par résolution DNS.
+func (v *Validator) checkPublicHost(host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !isPublicAddr(addr) {
+			return fmt.Errorf("%w: private or loopback address %s", ErrUnsafeURL, addr)
+		}
+		return nil
+	}
+
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: private or loopback host %q", ErrUnsafeURL, host)
+	}
+	if !v.opts.ResolveHosts {
+		return nil
+	}
+
+	// Un nom qui ne se résout pas ne désigne pas de cible privée : il est accepté,
+	// le moniteur signalera la destination comme inaccessible.
+	ctx, cancel := context.WithTimeout(context.Background(), v.opts.ResolveTimeout)
+	defer cancel()
+	addrs, err := v.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if !isPublicAddr(addr) {
+			return fmt.Errorf("%w: host %q resolves to private or loopback address %s", ErrUnsafeURL, host, addr)
+		}
+	}
+	return nil
+}
+
+// isPublicAddr indique si addr est une adresse unicast routable sur Internet.
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() || addr.IsMulticast() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// comparableHost normalise un hôte (ou une URL) pour la comparaison avec les hôtes du service,
+// sans port ni préfixe "www.".
+func comparableHost(host string) string {
+	if strings.Contains(host, "://") {
+		if u, err := url.Parse(host); err == nil {
+			host = u.Hostname()
+		}
+	} else if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimPrefix(normalizeDomain(host), "www.")
+}
@@ -0,0 +1,108 @@
+package useragent
+
+import "strings"
+
+// Classes d'appareils reconnues.
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+)
+
+// Other est la valeur utilisée quand le navigateur ou le système n'est pas reconnu.
+const Other = "Other"
+
+// Info contient les informations extraites d'un en-tête User-Agent.
+type Info struct {
+	Browser string // Famille de navigateur (Chrome, Firefox, Safari...)
+	OS      string // Système d'exploitation (Windows, macOS, iOS, Android...)
+	Device  string // Classe d'appareil (desktop, mobile, tablet, bot, unknown)
+}
+
+// rule associe un marqueur recherché dans le User-Agent à la valeur correspondante.
+// L'ordre des règles compte : les marqueurs les plus spécifiques doivent apparaître en premier
+// (Edge et Opera annoncent aussi Chrome, Chrome annonce aussi Safari, etc.).
+type rule struct {
+	marker string
+	value  string
+}
+
+var browserRules = []rule{
+	{"edg/", "Edge"},
+	{"edge/", "Edge"},
+	{"opr/", "Opera"},
+	{"opera", "Opera"},
+	{"samsungbrowser/", "Samsung Internet"},
+	{"yabrowser/", "Yandex"},
+	{"fxios/", "Firefox"},
+	{"firefox/", "Firefox"},
+	{"crios/", "Chrome"},
+	{"chromium/", "Chromium"},
+	{"chrome/", "Chrome"},
+	{"msie ", "Internet Explorer"},
+	{"trident/", "Internet Explorer"},
+	{"safari/", "Safari"},
+	{"curl/", "curl"},
+	{"wget/", "Wget"},
+}
+
+var osRules = []rule{
+	{"windows", "Windows"},
+	{"iphone", "iOS"},
+	{"ipad", "iOS"},
+	{"ipod", "iOS"},
+	{"android", "Android"},
+	{"cros", "ChromeOS"},
+	{"mac os x", "macOS"},
+	{"macintosh", "macOS"},
+	{"linux", "Linux"},
+}
+
+var botMarkers = []string{"bot", "crawler", "spider", "slurp", "curl/", "wget/", "python-requests", "go-http-client", "headless"}
+
+// Parse analyse un User-Agent avec des heuristiques simples, suffisantes pour des statistiques
+// agrégées. Une chaîne vide donne un navigateur et un système Other et un appareil unknown.
+func Parse(ua string) Info {
+	lower := strings.ToLower(ua)
+	info := Info{
+		Browser: match(lower, browserRules),
+		OS:      match(lower, osRules),
+	}
+
+	switch {
+	case lower == "":
+		info.Device = DeviceUnknown
+	case containsAny(lower, botMarkers):
+		info.Device = DeviceBot
+	case strings.Contains(lower, "ipad") || strings.Contains(lower, "tablet") ||
+		(strings.Contains(lower, "android") && !strings.Contains(lower, "mobile")):
+		info.Device = DeviceTablet
+	case strings.Contains(lower, "mobi") || strings.Contains(lower, "iphone") || strings.Contains(lower, "ipod"):
+		info.Device = DeviceMobile
+	default:
+		info.Device = DeviceDesktop
+	}
+	return info
+}
+
+// match retourne la valeur de la première règle dont le marqueur est présent dans ua.
+func match(ua string, rules []rule) string {
+	for _, r := range rules {
+		if strings.Contains(ua, r.marker) {
+			return r.value
+		}
+	}
+	return Other
+}
+
+// containsAny indique si ua contient au moins l'un des marqueurs.
+func containsAny(ua string, markers []string) bool {
+	for _, marker := range markers {
+		if strings.Contains(ua, marker) {
+			return true
+		}
+	}
+	return false
+}
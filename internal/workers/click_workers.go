@@ -1,46 +1,204 @@
 package workers
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/axellelanca/urlshortener/internal/hll"
+	"github.com/axellelanca/urlshortener/internal/logging"
+	"github.com/axellelanca/urlshortener/internal/metrics"
 	"github.com/axellelanca/urlshortener/internal/models"
 	"github.com/axellelanca/urlshortener/internal/repository" // Nécessaire pour interagir avec le ClickRepository
+	"github.com/axellelanca/urlshortener/internal/useragent"
 )
 
+// ClickWorkerConfig regroupe les paramètres du pool de workers de clics.
+type ClickWorkerConfig struct {
+	WorkerCount   int           // Nombre de goroutines workers
+	BatchSize     int           // Nombre d'événements déclenchant l'écriture d'un lot
+	FlushInterval time.Duration // Délai maximal avant l'écriture d'un lot incomplet
+	MaxRetries    int           // Nombre de nouvelles tentatives quand la base est verrouillée
+	RetryBackoff  time.Duration // Délai initial entre deux tentatives (doublé à chaque échec)
+	VisitorSalt   string        // Sel des empreintes utilisées pour le comptage des visiteurs uniques
+}
+
 // StartClickWorkers lance un pool de goroutines "workers" pour traiter les événements de clic.
 // Chaque worker lira depuis le même 'clickEventsChan' et utilisera le 'clickRepo' pour la persistance.
-func StartClickWorkers(workerCount int, clickEventsChan <-chan models.ClickEvent, clickRepo repository.ClickRepository) {
-	log.Printf("Starting %d click worker(s)...", workerCount)
-	for i := 0; i < workerCount; i++ {
+// Les événements sont accumulés puis écrits par lots, dès que BatchSize est atteint ou que
+// FlushInterval s'est écoulé, afin de limiter le nombre de transactions d'écriture.
+// Les workers s'arrêtent après avoir écrit leur dernier lot quand le channel est fermé
+// (vidange complète), ou dès l'annulation de ctx (les événements encore dans le channel
+// sont alors abandonnés) ; le WaitGroup retourné permet d'attendre leur arrêt.
+func StartClickWorkers(ctx context.Context, cfg ClickWorkerConfig, clickEventsChan <-chan models.ClickEvent, clickRepo repository.ClickRepository) *sync.WaitGroup {
+	// Valeurs de repli pour une configuration incomplète (un ticker exige une durée positive).
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	logging.Component("click_worker").Info("Démarrage des workers de clics",
+		"workers", cfg.WorkerCount, "batch_size", cfg.BatchSize, "flush_interval", cfg.FlushInterval.String())
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.WorkerCount; i++ {
 		// Lance chaque worker dans sa propre goroutine.
 		// Le channel est passé en lecture seule (<-chan) pour renforcer l'immutabilité du channel à l'intérieur du worker.
-		go clickWorker(clickEventsChan, clickRepo)
+		wg.Add(1)
+		logger := logging.Component("click_worker").With("worker", i)
+		go func() {
+			defer wg.Done()
+			clickWorker(ctx, cfg, clickEventsChan, clickRepo, logger)
+		}()
 	}
+	return &wg
 }
 
 // clickWorker est la fonction exécutée par chaque goroutine worker.
-// Elle tourne indéfiniment, lisant les événements de clic dès qu'ils sont disponibles dans le channel.
-func clickWorker(clickEventsChan <-chan models.ClickEvent, clickRepo repository.ClickRepository) {
-	for event := range clickEventsChan { // Boucle qui lit les événements du channel
-		// Conversion du ClickEvent en modèle Click
-		click := models.Click{
-			LinkID:    event.LinkID,
-			Timestamp: event.Timestamp,
-			UserAgent: event.UserAgent,
-			IPAddress: event.IPAddress,
+// Elle lit les événements de clic du channel et les accumule dans un lot, écrit en base
+// quand il est plein ou à chaque tick. Le lot en cours est écrit à la fermeture du channel
+// ou à l'annulation de ctx.
+func clickWorker(ctx context.Context, cfg ClickWorkerConfig, clickEventsChan <-chan models.ClickEvent, clickRepo repository.ClickRepository, logger *slog.Logger) {
+	salt := []byte(cfg.VisitorSalt)
+	batch := make([]models.ClickEvent, 0, cfg.BatchSize)
+
+	ticker := time.NewTicker(cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-clickEventsChan:
+			if !ok {
+				// Channel fermé : écrire ce qui reste avant de s'arrêter.
+				flushClicks(cfg, batch, clickRepo, salt, logger)
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= cfg.BatchSize {
+				flushClicks(cfg, batch, clickRepo, salt, logger)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				flushClicks(cfg, batch, clickRepo, salt, logger)
+				batch = batch[:0]
+			}
+		case <-ctx.Done():
+			// Arrêt forcé, le channel restant ouvert : écrire le lot en cours et s'arrêter.
+			flushClicks(cfg, batch, clickRepo, salt, logger)
+			return
 		}
+	}
+}
 
-		// Persister le clic en base de données via le clickRepo
-		err := clickRepo.CreateClick(&click)
+// flushClicks écrit un lot d'événements en base et journalise le résultat.
+// Les identifiants de requête des clics sont repris dans les logs pour relier
+// chaque redirection à son enregistrement.
+func flushClicks(cfg ClickWorkerConfig, events []models.ClickEvent, clickRepo repository.ClickRepository, salt []byte, logger *slog.Logger) {
+	if len(events) == 0 {
+		return
+	}
+	if err := persistClicks(cfg, events, clickRepo, salt, logger); err != nil {
+		// Si une erreur se produit lors de l'enregistrement, logguez-la.
+		metrics.WorkerFailures.Inc()
+		metrics.ClicksDropped.WithLabelValues("persist_failed").Add(float64(len(events)))
+		logger.Error("Échec de l'enregistrement du lot de clics",
+			"clicks", len(events), "request_ids", requestIDs(events), "error", err)
+		return
+	}
+	logger.Info("Lot de clics enregistré", "clicks", len(events))
+	for _, event := range events {
+		logger.Debug("Clic enregistré", "request_id", event.RequestID, "link_id", event.LinkID)
+	}
+}
+
+// requestIDs retourne les identifiants de requête non vides d'un lot d'événements.
+func requestIDs(events []models.ClickEvent) []string {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		if event.RequestID != "" {
+			ids = append(ids, event.RequestID)
+		}
+	}
+	return ids
+}
 
-		if err != nil {
-			// Si une erreur se produit lors de l'enregistrement, logguez-la.
-			log.Printf("ERROR: Failed to save click for LinkID %d (UserAgent: %s, IP: %s): %v",
-				event.LinkID, event.UserAgent, event.IPAddress, err)
+// persistClicks convertit un lot d'événements en clics et sketches de visiteurs, puis les
+// persiste en une transaction. Quand la base est verrouillée, l'écriture est réessayée
+// avec un délai exponentiel ; les autres erreurs sont retournées immédiatement.
+func persistClicks(cfg ClickWorkerConfig, events []models.ClickEvent, clickRepo repository.ClickRepository, salt []byte, logger *slog.Logger) error {
+	clicks := make([]models.Click, 0, len(events))
+	visitors := make(map[uint]*hll.Sketch)
+	for _, event := range events {
+		clicks = append(clicks, newClick(event))
 
-		} else {
-			// Log optionnel pour confirmer l'enregistrement (utile pour le débogage)
-			log.Printf("Click recorded successfully for LinkID %d", event.LinkID)
+		sketch, ok := visitors[event.LinkID]
+		if !ok {
+			sketch = hll.New()
+			visitors[event.LinkID] = sketch
 		}
+		sketch.Add(visitorHash(salt, event))
 	}
-}
\ No newline at end of file
+
+	backoff := cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := clickRepo.CreateClickBatch(clicks, visitors)
+		if err == nil {
+			metrics.ClicksRecorded.Add(float64(len(clicks)))
+			return nil
+		}
+		if !repository.IsBusyError(err) || attempt >= cfg.MaxRetries {
+			return fmt.Errorf("after %d attempt(s): %w", attempt+1, err)
+		}
+		// Délai exponentiel avec gigue pour désynchroniser les workers en concurrence.
+		wait := backoff + time.Duration(rand.Int64N(int64(backoff)+1))
+		logger.Warn("Base de données occupée, nouvelle tentative du lot de clics",
+			"clicks", len(clicks), "retry_in", wait.String(), "attempt", attempt+1, "max_retries", cfg.MaxRetries)
+		metrics.WorkerRetries.Inc()
+		time.Sleep(wait)
+		backoff *= 2
+	}
+}
+
+// newClick convertit un ClickEvent en modèle Click, en enrichissant l'événement
+// avec le domaine du referrer et les informations déduites du User-Agent.
+// L'analyse est faite ici, hors du chemin de redirection.
+func newClick(event models.ClickEvent) models.Click {
+	ua := useragent.Parse(event.UserAgent)
+	return models.Click{
+		LinkID:       event.LinkID,
+		Timestamp:    event.Timestamp.UTC(), // Stocké en UTC pour des comparaisons cohérentes en base
+		UserAgent:    truncate(event.UserAgent, 255),
+		IPAddress:    truncate(event.IPAddress, 50),
+		Referrer:     truncate(event.Referrer, 512),
+		ReferrerHost: truncate(referrerHost(event.Referrer), 255),
+		Browser:      ua.Browser,
+		OS:           ua.OS,
+		DeviceType:   ua.Device,
+	}
+}
+
+// referrerHost extrait le domaine d'un en-tête Referer, sans le préfixe "www.".
+func referrerHost(referrer string) string {
+	if referrer == "" {
+		return ""
+	}
+	u, err := url.Parse(referrer)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// truncate limite s à max octets pour respecter la taille des colonnes.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return strings.ToValidUTF8(s[:max], "")
+}
@@ -0,0 +1,59 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/logging"
+	"github.com/axellelanca/urlshortener/internal/repository"
+)
+
+// ExpirationSweeper marque périodiquement comme expirés les liens dont la date
+// d'expiration est dépassée ou dont le budget de clics est épuisé.
+// Les liens marqués sont ensuite exclus de la surveillance du moniteur d'URLs.
+type ExpirationSweeper struct {
+	linkRepo repository.LinkRepository // Pour marquer les liens expirés
+	interval time.Duration             // Intervalle entre deux passages
+	logger   *slog.Logger
+}
+
+// NewExpirationSweeper crée et retourne une nouvelle instance de ExpirationSweeper.
+func NewExpirationSweeper(linkRepo repository.LinkRepository, interval time.Duration) *ExpirationSweeper {
+	return &ExpirationSweeper{
+		linkRepo: linkRepo,
+		interval: interval,
+		logger:   logging.Component("sweeper"),
+	}
+}
+
+// Start lance la boucle de balayage périodique.
+// Cette fonction est conçue pour être lancée dans une goroutine séparée et se termine quand ctx est annulé.
+func (s *ExpirationSweeper) Start(ctx context.Context) {
+	s.logger.Info("Démarrage du balayage des liens expirés", "interval", s.interval.String())
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.sweep()
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Arrêt du balayage des liens expirés")
+			return
+		case <-ticker.C:
+			s.sweep()
+		}
+	}
+}
+
+// sweep effectue un passage de marquage des liens expirés.
+func (s *ExpirationSweeper) sweep() {
+	count, err := s.linkRepo.MarkExpiredLinks(time.Now())
+	if err != nil {
+		s.logger.Error("Échec du marquage des liens expirés", "error", err)
+		return
+	}
+	if count > 0 {
+		s.logger.Info("Liens marqués comme expirés", "count", count)
+	}
+}
@@ -0,0 +1,116 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/logging"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/spill"
+)
+
+// SpillReplayer relit les événements de clic mis de côté sur disque quand le channel
+// était plein, et les enregistre en base. La relecture a lieu au démarrage puis
+// périodiquement, dès que le channel est redescendu sous un quart de sa capacité.
+type SpillReplayer struct {
+	spillLog        *spill.Log
+	clickEventsChan <-chan models.ClickEvent // Observé pour mesurer la pression, jamais lu
+	clickRepo       repository.ClickRepository
+	cfg             ClickWorkerConfig
+	interval        time.Duration
+	logger          *slog.Logger
+}
+
+// NewSpillReplayer crée et retourne une nouvelle instance de SpillReplayer.
+func NewSpillReplayer(spillLog *spill.Log, clickEventsChan <-chan models.ClickEvent, clickRepo repository.ClickRepository,
+	cfg ClickWorkerConfig, interval time.Duration) *SpillReplayer {
+	return &SpillReplayer{
+		spillLog:        spillLog,
+		clickEventsChan: clickEventsChan,
+		clickRepo:       clickRepo,
+		cfg:             cfg,
+		interval:        interval,
+		logger:          logging.Component("spill"),
+	}
+}
+
+// Start relit les segments existants puis surveille la pression sur le channel.
+// Cette fonction est conçue pour être lancée dans une goroutine séparée et se termine quand ctx est annulé.
+func (r *SpillReplayer) Start(ctx context.Context) {
+	r.logger.Info("Démarrage de la relecture des clics débordés", "interval", r.interval.String())
+	r.replay(ctx)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Arrêt de la relecture des clics débordés")
+			return
+		case <-ticker.C:
+			if len(r.clickEventsChan) <= cap(r.clickEventsChan)/4 {
+				r.replay(ctx)
+			}
+		}
+	}
+}
+
+// replay scelle le segment en cours puis enregistre chaque segment scellé en une transaction.
+// Un segment n'est supprimé qu'une fois ses clics persistés ; en cas d'échec il sera
+// relu au passage suivant.
+func (r *SpillReplayer) replay(ctx context.Context) {
+	if err := r.spillLog.Seal(); err != nil {
+		r.logger.Error("Échec du scellement du segment actif", "error", err)
+		return
+	}
+	segments, err := r.spillLog.Segments()
+	if err != nil {
+		r.logger.Error("Échec de la liste des segments", "error", err)
+		return
+	}
+
+	salt := []byte(r.cfg.VisitorSalt)
+	for _, path := range segments {
+		if ctx.Err() != nil {
+			return
+		}
+
+		var events []models.ClickEvent
+		read, err := spill.ReadSegment(path, func(payload []byte) error {
+			var event models.ClickEvent
+			if err := json.Unmarshal(payload, &event); err != nil {
+				r.logger.Warn("Enregistrement illisible ignoré", "segment", path, "error", err)
+				return nil
+			}
+			events = append(events, event)
+			return nil
+		})
+		if err != nil {
+			r.logger.Error("Échec de la lecture du segment", "segment", path, "error", err)
+			return
+		}
+
+		if len(events) > 0 {
+			if err := persistClicks(r.cfg, events, r.clickRepo, salt, r.logger); err != nil {
+				r.logger.Error("Échec de l'enregistrement des clics du segment",
+					"segment", path, "clicks", len(events), "request_ids", requestIDs(events), "error", err)
+				return
+			}
+		}
+		if read.Corrupt() {
+			r.logger.Warn("Fin de segment illisible mise de côté",
+				"segment", path, "valid_bytes", read.ValidBytes, "corrupt_bytes", read.Size-read.ValidBytes)
+		}
+		if err := r.spillLog.Remove(path, read); err != nil {
+			r.logger.Error("Échec de la suppression du segment", "segment", path, "error", err)
+			return
+		}
+		r.logger.Info("Clics relus depuis le segment", "segment", path, "clicks", len(events))
+		for _, event := range events {
+			r.logger.Debug("Clic enregistré", "request_id", event.RequestID, "link_id", event.LinkID)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package workers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+// visitorHash calcule l'empreinte d'un visiteur pour le comptage des visiteurs uniques.
+// Il s'agit d'un HMAC-SHA256 salé du jour (UTC), de l'IP et du User-Agent : la paire brute
+// n'est jamais conservée pour cet usage et l'empreinte change chaque jour.
+func visitorHash(salt []byte, event models.ClickEvent) uint64 {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(event.Timestamp.UTC().Format("2006-01-02")))
+	mac.Write([]byte{0})
+	mac.Write([]byte(event.IPAddress))
+	mac.Write([]byte{0})
+	mac.Write([]byte(event.UserAgent))
+	return binary.BigEndian.Uint64(mac.Sum(nil)[:8])
+}